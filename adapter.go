@@ -19,9 +19,30 @@ type Adapter interface {
 	Delete(ctx context.Context, query Query) (int, error)
 	Exec(ctx context.Context, stmt string, args []any) (int64, int64, error)
 
-	Begin(ctx context.Context) (Adapter, error)
+	Begin(ctx context.Context, options TransactionOptions) (Adapter, error)
 	Commit(ctx context.Context) error
 	Rollback(ctx context.Context) error
 
+	// Savepoint creates a named savepoint inside the current transaction.
+	Savepoint(ctx context.Context, name string) error
+	// RollbackTo rolls back the current transaction to the named savepoint.
+	RollbackTo(ctx context.Context, name string) error
+	// Release destroys the named savepoint, keeping changes made after it was created.
+	Release(ctx context.Context, name string) error
+
 	Apply(ctx context.Context, migration Migration) error
 }
+
+// PartitionLimiter is an optional interface implemented by adapter that evaluates Query.PartitionLimitQuery,
+// such as using window function or lateral join. Repository falls back to one query per parent
+// when preloading with PreloadLimit using adapter that doesn't support it.
+type PartitionLimiter interface {
+	SupportPartitionLimit() bool
+}
+
+// SupportPartitionLimit returns true if adapter implements PartitionLimiter and evaluates partition limit.
+// Adapter that wraps another adapter can use it to forward PartitionLimiter to the wrapped adapter.
+func SupportPartitionLimit(adapter Adapter) bool {
+	limiter, ok := adapter.(PartitionLimiter)
+	return ok && limiter.SupportPartitionLimit()
+}
@@ -61,8 +61,8 @@ func (ta *testAdapter) Delete(ctx context.Context, query Query) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
-func (ta *testAdapter) Begin(ctx context.Context) (Adapter, error) {
-	args := ta.Called()
+func (ta *testAdapter) Begin(ctx context.Context, options TransactionOptions) (Adapter, error) {
+	args := ta.Called(options)
 	return ta, args.Error(0)
 }
 
@@ -76,6 +76,21 @@ func (ta *testAdapter) Rollback(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (ta *testAdapter) Savepoint(ctx context.Context, name string) error {
+	args := ta.Called(name)
+	return args.Error(0)
+}
+
+func (ta *testAdapter) RollbackTo(ctx context.Context, name string) error {
+	args := ta.Called(name)
+	return args.Error(0)
+}
+
+func (ta *testAdapter) Release(ctx context.Context, name string) error {
+	args := ta.Called(name)
+	return args.Error(0)
+}
+
 func (ta *testAdapter) Apply(ctx context.Context, migration Migration) error {
 	args := ta.Called(migration)
 	return args.Error(0)
@@ -34,6 +34,8 @@ func (a Association) document(lazy bool) (*Document, bool) {
 	)
 
 	switch rv.Kind() {
+	case reflect.Interface:
+		return a.polymorphicDocument(rv, lazy)
 	case reflect.Ptr:
 		if rv.IsNil() {
 			if !lazy {
@@ -57,6 +59,48 @@ func (a Association) document(lazy bool) (*Document, bool) {
 	}
 }
 
+// polymorphicDocument returns target of polymorphic belongs to association stored in interface field.
+// The returned document is nil when interface is nil and its type field is not registered using RegisterPolymorphic.
+func (a Association) polymorphicDocument(rv reflect.Value, lazy bool) (*Document, bool) {
+	if !rv.IsNil() {
+		var (
+			doc = NewDocument(rv.Elem())
+		)
+
+		return doc, doc.Persisted()
+	}
+
+	rt, ok := polymorphicType(a.PolymorphicValue())
+	if !ok {
+		return nil, false
+	}
+
+	var (
+		target = reflect.New(rt)
+	)
+
+	if !lazy {
+		rv.Set(target)
+	}
+
+	return NewDocument(target), false
+}
+
+// polymorphicTarget returns preload target of polymorphic belongs to association,
+// UnknownPolymorphicTypeError is returned when its type is not registered.
+func (a Association) polymorphicTarget() (slice, bool, error) {
+	var (
+		rv     = reflectValueFieldByIndex(a.rv, a.meta.targetIndex, true)
+		rt, ok = polymorphicType(a.PolymorphicValue())
+	)
+
+	if !ok {
+		return nil, false, UnknownPolymorphicTypeError{Type: a.PolymorphicValue()}
+	}
+
+	return polymorphicTarget{Document: newZeroDocument(rt), rv: rv}, !rv.IsNil(), nil
+}
+
 // Collection returns association target as collection.
 // If association is zero, second return value will be false.
 func (a Association) Collection() (*Collection, bool) {
@@ -116,6 +160,15 @@ func (a Association) ForeignValue() any {
 		rv = reflectValueFieldByIndex(a.rv, a.meta.targetIndex, false)
 	)
 
+	if rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+
+		value, _ := NewDocument(rv.Elem()).Value(a.meta.foreignField)
+		return value
+	}
+
 	if rv.Kind() == reflect.Ptr {
 		rv = rv.Elem()
 	}
@@ -128,6 +181,22 @@ func (a Association) Through() string {
 	return a.meta.Through()
 }
 
+// PolymorphicField returns type field of polymorphic association, empty if association is not polymorphic.
+func (a Association) PolymorphicField() string {
+	return a.meta.PolymorphicField()
+}
+
+// PolymorphicValue returns table name of polymorphic association target.
+// For belongs to, it's the value of type field stored in the entity.
+func (a Association) PolymorphicValue() string {
+	if !a.meta.isPolymorphicBelongsTo() {
+		return a.meta.PolymorphicValue()
+	}
+
+	value, _ := indirectInterface(reflectValueFieldByIndex(a.rv, a.meta.polymorphicIndex, false)).(string)
+	return value
+}
+
 // Autoload assoc setting when parent is loaded.
 func (a Association) Autoload() bool {
 	return a.meta.Autoload()
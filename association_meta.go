@@ -37,6 +37,10 @@ type cachedAssociationMeta struct {
 	through        string
 	autoload       bool
 	autosave       bool
+	// polymorphic type field, stored in the entity for belongs to, and in the target for has one and has many.
+	polymorphicField string
+	polymorphicIndex []int
+	polymorphicValue string
 }
 
 type AssociationMeta struct {
@@ -74,8 +78,28 @@ func (am AssociationMeta) Autosave() bool {
 	return am.autosave
 }
 
+// PolymorphicField returns type field of polymorphic association, empty if association is not polymorphic.
+func (am AssociationMeta) PolymorphicField() string {
+	return am.polymorphicField
+}
+
+// PolymorphicValue returns type value that identifies the entity in polymorphic has one or has many association.
+// It's empty for polymorphic belongs to, because the value is stored in each entity.
+func (am AssociationMeta) PolymorphicValue() string {
+	return am.polymorphicValue
+}
+
+// isPolymorphicBelongsTo returns true if association target is resolved using type field of the entity.
+func (am AssociationMeta) isPolymorphicBelongsTo() bool {
+	return am.polymorphicField != "" && am.typ == BelongsTo
+}
+
 // Document returns association target document meta.
 func (am AssociationMeta) DocumentMeta() DocumentMeta {
+	if am.isPolymorphicBelongsTo() {
+		panic("rel: document meta of polymorphic belongs to association is resolved per entity")
+	}
+
 	var (
 		rt = am.rt.FieldByIndex(am.targetIndex).Type
 	)
@@ -120,14 +144,24 @@ func getAssociationMeta(rt reflect.Type, index []int) AssociationMeta {
 		}
 	)
 
-	if assocMeta.autosave && assocMeta.through != "" {
-		panic("rel: autosave is not supported for has one/has many through association")
+	if assocMeta.autosave && assocMeta.through != "" && sf.Type.Kind() != reflect.Slice && (sf.Type.Kind() != reflect.Ptr || sf.Type.Elem().Kind() != reflect.Slice) {
+		panic("rel: autosave is not supported for has one through association")
 	}
 
 	for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice {
 		ft = ft.Elem()
 	}
 
+	if polymorphic := sf.Tag.Get("polymorphic"); polymorphic != "" {
+		assocMeta = getPolymorphicAssociationMeta(rt, sf, ft, polymorphic, assocMeta)
+		associationMetaCache.Store(key, assocMeta)
+
+		return AssociationMeta{
+			rt:                    rt,
+			cachedAssociationMeta: assocMeta,
+		}
+	}
+
 	var (
 		refDocMeta = getDocumentMeta(rt, true)
 		fkDocMeta  = getDocumentMeta(ft, true)
@@ -180,3 +214,94 @@ func getAssociationMeta(rt reflect.Type, index []int) AssociationMeta {
 		cachedAssociationMeta: assocMeta,
 	}
 }
+
+// getPolymorphicAssociationMeta infers polymorphic association using `polymorphic:"name"` tag.
+// Interface field is a belongs to association that stores name_id and name_type field in the entity,
+// otherwise it's has one or has many association that stores the fields in the target, with entity's table as type value.
+func getPolymorphicAssociationMeta(rt reflect.Type, sf reflect.StructField, ft reflect.Type, polymorphic string, assocMeta cachedAssociationMeta) cachedAssociationMeta {
+	if assocMeta.through != "" {
+		panic("rel: polymorphic is not supported for has one/has many through association")
+	}
+
+	var (
+		ref         = sf.Tag.Get("ref")
+		fk          = sf.Tag.Get("fk")
+		typeField   = polymorphic + "_type"
+		refDocMeta  = getDocumentMeta(rt, true)
+		typeDocMeta DocumentMeta
+	)
+
+	if ft.Kind() == reflect.Interface {
+		if ref == "" {
+			ref = polymorphic + "_id"
+		}
+
+		if fk == "" {
+			fk = "id"
+		}
+
+		assocMeta.typ = BelongsTo
+		typeDocMeta = refDocMeta
+	} else {
+		if ref == "" {
+			ref = "id"
+		}
+
+		if fk == "" {
+			fk = polymorphic + "_id"
+		}
+
+		if sf.Type.Kind() == reflect.Slice || (sf.Type.Kind() == reflect.Ptr && sf.Type.Elem().Kind() == reflect.Slice) {
+			assocMeta.typ = HasMany
+		} else {
+			assocMeta.typ = HasOne
+		}
+
+		typeDocMeta = getDocumentMeta(ft, true)
+		assocMeta.polymorphicValue = refDocMeta.table
+
+		if id, exist := typeDocMeta.index[fk]; !exist {
+			panic("rel: foreign_key (" + fk + ") field not found")
+		} else {
+			assocMeta.foreignIndex = id
+		}
+	}
+
+	if id, exist := refDocMeta.index[ref]; !exist {
+		panic("rel: references (" + ref + ") field not found ")
+	} else {
+		assocMeta.referenceIndex = id
+		assocMeta.referenceField = ref
+	}
+
+	if id, exist := typeDocMeta.index[typeField]; !exist {
+		panic("rel: polymorphic type (" + typeField + ") field not found")
+	} else {
+		assocMeta.polymorphicIndex = id
+		assocMeta.polymorphicField = typeField
+	}
+
+	assocMeta.foreignField = fk
+
+	return assocMeta
+}
+
+// throughTargetField returns field of intermediary entity that references target of many to many association.
+// It's inferred from target name (eg: role_id), or the other field of intermediary entity with composite primary key.
+func throughTargetField(throughMeta DocumentMeta, throughField string, targetMeta DocumentMeta) string {
+	if field := snaker.CamelToSnake(targetMeta.rt.Name()) + "_id"; field != throughField {
+		if _, exist := throughMeta.index[field]; exist {
+			return field
+		}
+	}
+
+	if len(throughMeta.primaryField) == 2 {
+		for _, field := range throughMeta.primaryField {
+			if field != throughField {
+				return field
+			}
+		}
+	}
+
+	panic("rel: cannot infer field of " + throughMeta.Table() + " that references " + targetMeta.Table())
+}
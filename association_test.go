@@ -346,6 +346,22 @@ func TestAssociation_autosaveWithThrough(t *testing.T) {
 	})
 }
 
+func TestThroughTargetField(t *testing.T) {
+	var (
+		userRoles = NewDocument(&UserRole{}).Meta()
+		follows   = NewDocument(&Follow{}).Meta()
+		roles     = NewDocument(&Role{}).Meta()
+		users     = NewDocument(&User{}).Meta()
+	)
+
+	assert.Equal(t, "role_id", throughTargetField(userRoles, "user_id", roles))
+	assert.Equal(t, "user_id", throughTargetField(userRoles, "role_id", users))
+	assert.Equal(t, "follower_id", throughTargetField(follows, "following_id", users))
+	assert.Panics(t, func() {
+		throughTargetField(roles, "id", users)
+	})
+}
+
 func TestAssociation_refNotFound(t *testing.T) {
 	type Alpha struct {
 		ID int
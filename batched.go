@@ -0,0 +1,223 @@
+package rel
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// batchedRepository coalesces concurrent Find by a single field into one FindAll query.
+type batchedRepository struct {
+	Repository
+	window  time.Duration
+	lock    sync.Mutex
+	batches map[batchKey]*batch
+}
+
+// Batched returns repository that coalesces concurrent Find lookups by primary or foreign key within the window,
+// eg: Find(ctx, &user, where.Eq("id", 1)), into a single FindAll query using In filter, and fans the results back out.
+// Find with any other query is passed to the underlying repository.
+// Lookups are only batched when they share the same adapter, table resolver, tenant and default scope from the context,
+// so lookups inside a transaction are never batched with lookups outside of it.
+func Batched(repository Repository, window time.Duration) Repository {
+	return &batchedRepository{
+		Repository: repository,
+		window:     window,
+		batches:    make(map[batchKey]*batch),
+	}
+}
+
+// batchKey identifies lookups that can be executed using the same query.
+type batchKey struct {
+	adapter Adapter
+	table   string
+	field   string
+	scope   string
+	rt      reflect.Type
+}
+
+type batch struct {
+	ctx    context.Context
+	values []any
+	index  map[any]struct{}
+	done   chan struct{}
+	result reflect.Value
+	err    error
+}
+
+func (br *batchedRepository) Find(ctx context.Context, entity any, queriers ...Querier) error {
+	var (
+		doc              = NewDocument(entity)
+		field, value, ok = batchLookup(doc.Meta(), Build(doc.Table(), queriers...))
+	)
+
+	if !ok {
+		return br.Repository.Find(ctx, entity, queriers...)
+	}
+
+	key, ok := br.batchKey(ctx, doc.Meta(), field)
+	if !ok {
+		return br.Repository.Find(ctx, entity, queriers...)
+	}
+
+	b := br.enqueue(ctx, key, value)
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if b.err != nil {
+		return b.err
+	}
+
+	for i := 0; i < b.result.Len(); i++ {
+		rv := b.result.Index(i)
+		if v, _ := NewDocument(rv.Addr().Interface()).Value(field); v == value {
+			doc.rv.Set(rv)
+			return nil
+		}
+	}
+
+	return NotFoundError{}
+}
+
+func (br *batchedRepository) MustFind(ctx context.Context, entity any, queriers ...Querier) {
+	must(br.Find(ctx, entity, queriers...))
+}
+
+// enqueue adds value to the pending batch of the key, the first lookup schedules the batch to be executed after the window.
+func (br *batchedRepository) enqueue(ctx context.Context, key batchKey, value any) *batch {
+	br.lock.Lock()
+	defer br.lock.Unlock()
+
+	b, ok := br.batches[key]
+	if !ok {
+		b = &batch{
+			ctx:   context.WithoutCancel(ctx),
+			index: make(map[any]struct{}),
+			done:  make(chan struct{}),
+		}
+
+		br.batches[key] = b
+		time.AfterFunc(br.window, func() {
+			br.lock.Lock()
+			delete(br.batches, key)
+			br.lock.Unlock()
+
+			b.result, b.err = br.findAll(b.ctx, key, b.values)
+			close(b.done)
+		})
+	}
+
+	if _, exists := b.index[value]; !exists {
+		b.index[value] = struct{}{}
+		b.values = append(b.values, value)
+	}
+
+	return b
+}
+
+// findAll fetches entities of the key by the values, the values are split into multiple queries if it's more than inClauseLength.
+func (br *batchedRepository) findAll(ctx context.Context, key batchKey, values []any) (reflect.Value, error) {
+	var (
+		inClauseLength = 999
+		result         = reflect.MakeSlice(reflect.SliceOf(key.rt), 0, len(values))
+	)
+
+	for len(values) > 0 {
+		if len(values) < inClauseLength {
+			inClauseLength = len(values)
+		}
+
+		chunk := reflect.New(reflect.SliceOf(key.rt))
+		if err := br.Repository.FindAll(ctx, chunk.Interface(), In(key.field, values[:inClauseLength]...)); err != nil {
+			return result, err
+		}
+
+		result = reflect.AppendSlice(result, chunk.Elem())
+		values = values[inClauseLength:]
+	}
+
+	return result, nil
+}
+
+// batchKey returns key of the lookup, the second return value is false if adapter in the context can't be used as key.
+func (br *batchedRepository) batchKey(ctx context.Context, meta DocumentMeta, field string) (batchKey, bool) {
+	var (
+		adapter = br.Repository.Adapter(ctx)
+		table   = meta.Table()
+		scope   = withTenantScope(ctx, meta.tenantField, withEntityScope(ctx, table, Build(table)))
+	)
+
+	if ra, ok := adapter.(tableResolverAdapter); ok {
+		adapter = ra.Adapter
+		table = ra.resolve(table)
+	}
+
+	if adapter == nil || !reflect.TypeOf(adapter).Comparable() {
+		return batchKey{}, false
+	}
+
+	return batchKey{
+		adapter: adapter,
+		table:   table,
+		field:   field,
+		scope:   scope.String(),
+		rt:      meta.rt,
+	}, true
+}
+
+// batchLookup returns field and value of query that only filters a field by equality.
+// The value is converted to type of the field, so it can be compared with value of the fetched entities.
+func batchLookup(meta DocumentMeta, query Query) (string, any, bool) {
+	var (
+		filter = query.WhereQuery
+	)
+
+	if filter.Type != FilterEqOp || query.LimitQuery > 1 {
+		return "", nil, false
+	}
+
+	switch filter.Value.(type) {
+	case nil, FieldExpr, Query, SubQuery:
+		return "", nil, false
+	}
+
+	query.LimitQuery = 0
+	if !reflect.DeepEqual(query, Build(meta.Table(), Eq(filter.Field, filter.Value))) {
+		return "", nil, false
+	}
+
+	ft, ok := meta.Type(filter.Field)
+	if !ok || !ft.Comparable() {
+		return "", nil, false
+	}
+
+	rv := reflect.ValueOf(filter.Value)
+	if rv.Type() != ft {
+		if kindClass(rv.Kind()) != kindClass(ft.Kind()) || !rv.Type().ConvertibleTo(ft) {
+			return "", nil, false
+		}
+
+		rv = rv.Convert(ft)
+	}
+
+	return filter.Field, rv.Interface(), true
+}
+
+// kindClass groups kinds that can be safely converted between each other.
+func kindClass(kind reflect.Kind) reflect.Kind {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.Uint
+	case reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+
+	return kind
+}
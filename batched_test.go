@@ -0,0 +1,231 @@
+package rel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBatched_Find(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = Batched(New(adapter), 20*time.Millisecond)
+		cur     = &testCursor{}
+		users   = make([]User, 4)
+		errs    = make([]error, 4)
+		ids     = []int{1, 2, 1, 3}
+		wg      sync.WaitGroup
+	)
+
+	adapter.On("Query", mock.MatchedBy(func(query Query) bool {
+		return query.Table == "users" && query.WhereQuery.Type == FilterInOp && query.WhereQuery.Field == "id" &&
+			assert.ElementsMatch(t, []any{1, 2, 3}, query.WhereQuery.Value)
+	})).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "name"}, nil).Once()
+	cur.On("Next").Return(true).Twice()
+	cur.MockScan(1, "alice").Once()
+	cur.MockScan(2, "bob").Once()
+	cur.On("Next").Return(false).Once()
+
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Find(context.TODO(), &users[i], Eq("id", ids[i]))
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Nil(t, errs[0])
+	assert.Equal(t, User{ID: 1, Name: "alice"}, users[0])
+	assert.Nil(t, errs[1])
+	assert.Equal(t, User{ID: 2, Name: "bob"}, users[1])
+	assert.Nil(t, errs[2])
+	assert.Equal(t, User{ID: 1, Name: "alice"}, users[2])
+	assert.Equal(t, NotFoundError{}, errs[3])
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestBatched_Find_error(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = Batched(New(adapter), time.Millisecond)
+		user    User
+		err     = errors.New("error")
+	)
+
+	adapter.On("Query", From("users").Where(In("id", 1))).Return(&testCursor{}, err).Once()
+
+	assert.Equal(t, err, repo.Find(context.TODO(), &user, Eq("id", 1)))
+	assert.Panics(t, func() {
+		adapter.On("Query", From("users").Where(In("id", 1))).Return(&testCursor{}, err).Once()
+		repo.MustFind(context.TODO(), &user, Eq("id", 1))
+	})
+
+	adapter.AssertExpectations(t)
+}
+
+func TestBatched_Find_canceled(t *testing.T) {
+	var (
+		adapter     = &testAdapter{}
+		repo        = Batched(New(adapter), 20*time.Millisecond)
+		user        User
+		ctx, cancel = context.WithCancel(context.TODO())
+	)
+
+	adapter.On("Query", From("users").Where(In("id", 1))).Return(createCursor(0), nil).Once()
+
+	cancel()
+	assert.Equal(t, context.Canceled, repo.Find(ctx, &user, Eq("id", 1)))
+
+	time.Sleep(40 * time.Millisecond)
+	adapter.AssertExpectations(t)
+}
+
+func TestBatched_Find_notBatched(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = Batched(New(adapter), time.Hour)
+		user    User
+		cur     = createCursor(1)
+	)
+
+	adapter.On("Query", From("users").Where(Eq("id", 10), Eq("name", "rel")).Limit(1)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.Find(context.TODO(), &user, Eq("id", 10), Eq("name", "rel")))
+	assert.Equal(t, 10, user.ID)
+	assert.False(t, cur.Next())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestBatched_findAll(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = Batched(New(adapter), time.Hour).(*batchedRepository)
+		meta    = NewDocument(&User{}).Meta()
+		values  = make([]any, 1000)
+		failure = errors.New("error")
+	)
+
+	for i := range values {
+		values[i] = i + 1
+	}
+
+	key, ok := repo.batchKey(context.TODO(), meta, "id")
+	assert.True(t, ok)
+
+	adapter.On("Query", From("users").Where(In("id", values[:999]...))).Return(createCursor(1), nil).Once()
+	adapter.On("Query", From("users").Where(In("id", values[999:]...))).Return(createCursor(1), nil).Once()
+
+	result, err := repo.findAll(context.TODO(), key, values)
+	assert.Nil(t, err)
+	assert.Equal(t, []User{{ID: 10}, {ID: 10}}, result.Interface())
+
+	adapter.On("Query", From("users").Where(In("id", values[:999]...))).Return(&testCursor{}, failure).Once()
+
+	_, err = repo.findAll(context.TODO(), key, values)
+	assert.Equal(t, failure, err)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestBatchLookup(t *testing.T) {
+	type Account struct {
+		ID     int64
+		UserID *int
+		Name   string
+	}
+
+	meta := NewDocument(&Account{}).Meta()
+
+	tests := []struct {
+		name     string
+		queriers []Querier
+		field    string
+		value    any
+		ok       bool
+	}{
+		{
+			name:     "primary key",
+			queriers: []Querier{Eq("id", 1)},
+			field:    "id",
+			value:    int64(1),
+			ok:       true,
+		},
+		{
+			name:     "foreign key",
+			queriers: []Querier{Where(Eq("user_id", 2)), Limit(1)},
+			field:    "user_id",
+			value:    2,
+			ok:       true,
+		},
+		{
+			name:     "mismatched kind",
+			queriers: []Querier{Eq("name", 1)},
+		},
+		{
+			name:     "field expression",
+			queriers: []Querier{Eq("name", Field("user_id"))},
+		},
+		{
+			name:     "multiple filters",
+			queriers: []Querier{Eq("id", 1), Eq("name", "rel")},
+		},
+		{
+			name:     "sort",
+			queriers: []Querier{Eq("id", 1), NewSortAsc("id")},
+		},
+		{
+			name:     "unscoped",
+			queriers: []Querier{Eq("id", 1), Unscoped(true)},
+		},
+		{
+			name:     "unknown field",
+			queriers: []Querier{Eq("email", "rel")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			field, value, ok := batchLookup(meta, Build(meta.Table(), test.queriers...))
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.field, field)
+			assert.Equal(t, test.value, value)
+		})
+	}
+}
+
+func TestBatched_batchKey(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		other   = &testAdapter{}
+		repo    = Batched(New(adapter), time.Hour).(*batchedRepository)
+		meta    = NewDocument(&User{}).Meta()
+		ctx     = context.TODO()
+	)
+
+	key, ok := repo.batchKey(ctx, meta, "id")
+	assert.True(t, ok)
+	assert.Equal(t, batchKey{adapter: adapter, table: "users", field: "id", scope: From("users").String(), rt: meta.rt}, key)
+
+	txKey, ok := repo.batchKey(wrapContext(ctx, contextData{adapter: other}).ctx, meta, "id")
+	assert.True(t, ok)
+	assert.Same(t, other, txKey.adapter)
+
+	schemaKey, ok := repo.batchKey(WithSchema(ctx, "tenant_42"), meta, "id")
+	assert.True(t, ok)
+	assert.Equal(t, "tenant_42.users", schemaKey.table)
+	assert.Equal(t, adapter, schemaKey.adapter)
+}
@@ -73,7 +73,7 @@ func (c Changeset) Apply(doc *Document, mut *Mutation) {
 		)
 
 		if c.valueChanged(typ, old, new) {
-			mut.Add(Set(field, new))
+			mut.Add(Set(field, wrapJSON(c.doc.meta, field, new)))
 		}
 	}
 
@@ -122,6 +122,9 @@ func (c Changeset) applyAssocMany(field string, mut *Mutation) {
 			muts       = make([]Mutation, 0, col.Len())
 			updatedIDs = make(map[any]struct{})
 			deletedIDs []any
+			changed    bool
+			// many to many association keeps mutation of every target to resolve its links.
+			through = assoc.Through() != ""
 		)
 
 		for i := 0; i < col.Len(); i++ {
@@ -133,11 +136,15 @@ func (c Changeset) applyAssocMany(field string, mut *Mutation) {
 			if ch, ok := chs[pValue]; ok {
 				updatedIDs[pValue] = struct{}{}
 
-				if amod := Apply(doc, ch); !amod.IsEmpty() {
+				amod := Apply(doc, ch)
+				if !amod.IsEmpty() || through {
 					muts = append(muts, amod)
 				}
+
+				changed = changed || !amod.IsEmpty()
 			} else {
 				muts = append(muts, Apply(doc, newStructset(doc, false)))
+				changed = true
 			}
 		}
 
@@ -150,7 +157,7 @@ func (c Changeset) applyAssocMany(field string, mut *Mutation) {
 			}
 		}
 
-		if len(muts) > 0 || len(deletedIDs) > 0 {
+		if changed || len(deletedIDs) > 0 {
 			mut.SetAssoc(field, muts...)
 			mut.SetDeletedIDs(field, deletedIDs)
 		}
@@ -0,0 +1,96 @@
+package rel
+
+import (
+	"strings"
+)
+
+// CombinationQuery combines result of a query with result of another query using set operation.
+// Supported operators are UNION, UNION ALL, INTERSECT, INTERSECT ALL, EXCEPT and EXCEPT ALL.
+//
+// Combinations are applied in order, and sort, offset and limit of the main query applies to the combined result.
+type CombinationQuery struct {
+	Operator string
+	Query    Query
+}
+
+// Build query.
+func (cq CombinationQuery) Build(query *Query) {
+	query.CombinationQuery = append(query.CombinationQuery, cq)
+}
+
+// String representation.
+func (cq CombinationQuery) String() string {
+	var builder strings.Builder
+
+	if method := cq.method(); method != "" {
+		builder.WriteString("rel.")
+		builder.WriteString(method)
+		builder.WriteByte('(')
+	} else {
+		builder.WriteString("rel.CombineWith(\"")
+		builder.WriteString(cq.Operator)
+		builder.WriteString("\", ")
+	}
+
+	builder.WriteString(cq.Query.String())
+	builder.WriteByte(')')
+
+	return builder.String()
+}
+
+func (cq CombinationQuery) method() string {
+	switch cq.Operator {
+	case "UNION":
+		return "Union"
+	case "UNION ALL":
+		return "UnionAll"
+	case "INTERSECT":
+		return "Intersect"
+	case "INTERSECT ALL":
+		return "IntersectAll"
+	case "EXCEPT":
+		return "Except"
+	case "EXCEPT ALL":
+		return "ExceptAll"
+	default:
+		return ""
+	}
+}
+
+// NewCombination combines result with another query using given set operator.
+func NewCombination(operator string, query Query) CombinationQuery {
+	return CombinationQuery{
+		Operator: operator,
+		Query:    query,
+	}
+}
+
+// NewUnion combines result with another query using UNION.
+func NewUnion(query Query) CombinationQuery {
+	return NewCombination("UNION", query)
+}
+
+// NewUnionAll combines result with another query using UNION ALL.
+func NewUnionAll(query Query) CombinationQuery {
+	return NewCombination("UNION ALL", query)
+}
+
+// NewIntersect combines result with another query using INTERSECT.
+func NewIntersect(query Query) CombinationQuery {
+	return NewCombination("INTERSECT", query)
+}
+
+// NewIntersectAll combines result with another query using INTERSECT ALL.
+func NewIntersectAll(query Query) CombinationQuery {
+	return NewCombination("INTERSECT ALL", query)
+}
+
+// NewExcept combines result with another query using EXCEPT.
+func NewExcept(query Query) CombinationQuery {
+	return NewCombination("EXCEPT", query)
+}
+
+// NewExceptAll combines result with another query using EXCEPT ALL.
+func NewExceptAll(query Query) CombinationQuery {
+	return NewCombination("EXCEPT ALL", query)
+}
@@ -0,0 +1,43 @@
+package rel_test
+
+import (
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombinationQuery_String(t *testing.T) {
+	var (
+		query = rel.From("admins")
+	)
+
+	assert.Equal(t, "rel.Union(rel.From(\"admins\"))", rel.NewUnion(query).String())
+	assert.Equal(t, "rel.UnionAll(rel.From(\"admins\"))", rel.NewUnionAll(query).String())
+	assert.Equal(t, "rel.Intersect(rel.From(\"admins\"))", rel.NewIntersect(query).String())
+	assert.Equal(t, "rel.IntersectAll(rel.From(\"admins\"))", rel.NewIntersectAll(query).String())
+	assert.Equal(t, "rel.Except(rel.From(\"admins\"))", rel.NewExcept(query).String())
+	assert.Equal(t, "rel.ExceptAll(rel.From(\"admins\"))", rel.NewExceptAll(query).String())
+	assert.Equal(t, "rel.CombineWith(\"MINUS\", rel.From(\"admins\"))", rel.NewCombination("MINUS", query).String())
+}
+
+func TestQuery_Combination(t *testing.T) {
+	var (
+		users   = rel.From("users")
+		admins  = rel.From("admins")
+		banned  = rel.From("banned_users")
+		members = rel.From("members")
+	)
+
+	assert.Equal(t, []rel.CombinationQuery{
+		{Operator: "UNION ALL", Query: admins},
+		{Operator: "INTERSECT", Query: members},
+		{Operator: "INTERSECT ALL", Query: members},
+		{Operator: "EXCEPT", Query: banned},
+		{Operator: "EXCEPT ALL", Query: banned},
+	}, users.UnionAll(admins).Intersect(members).IntersectAll(members).Except(banned).ExceptAll(banned).CombinationQuery)
+
+	assert.Equal(t, users.UnionAll(admins).UnionAll(members), rel.UnionAll(users, admins, members))
+	assert.Equal(t, users.Intersect(members), rel.Intersect(users, members))
+	assert.Equal(t, users.Except(banned).Except(admins), rel.Except(users, banned, admins))
+}
@@ -6,30 +6,42 @@ import (
 
 type contextKey int8
 
+// contextData stores transaction state that is passed using context.
+type contextData struct {
+	adapter   Adapter
+	depth     int
+	options   TransactionOptions
+	callbacks *transactionCallbacks
+}
+
 type contextWrapper struct {
-	ctx     context.Context
-	adapter Adapter
+	ctx context.Context
+	contextData
 }
 
 var ctxKey contextKey
 
 // fetchContext and use adapter passed by context if exists.
 // it stores contextData values to struct for fast repeated access.
+// adapter is wrapped with table resolver when the context carries one.
 func fetchContext(ctx context.Context, adapter Adapter) contextWrapper {
-	if adp, ok := ctx.Value(ctxKey).(Adapter); ok {
-		adapter = adp
+	data, ok := ctx.Value(ctxKey).(contextData)
+	if !ok {
+		data = contextData{adapter: adapter}
 	}
 
+	data.adapter = withTableResolver(ctx, data.adapter)
+
 	return contextWrapper{
-		ctx:     ctx,
-		adapter: adapter,
+		ctx:         ctx,
+		contextData: data,
 	}
 }
 
-// wrapContext wraps adapter inside context.
-func wrapContext(ctx context.Context, adapter Adapter) contextWrapper {
+// wrapContext wraps adapter and transaction state inside context.
+func wrapContext(ctx context.Context, data contextData) contextWrapper {
 	return contextWrapper{
-		ctx:     context.WithValue(ctx, ctxKey, adapter),
-		adapter: adapter,
+		ctx:         context.WithValue(ctx, ctxKey, data),
+		contextData: data,
 	}
 }
@@ -18,20 +18,25 @@ func TestContextWrapper(t *testing.T) {
 		cw = fetchContext(ctx, adapter)
 		assert.Equal(t, ctx, cw.ctx)
 		assert.Equal(t, adapter, cw.adapter)
+		assert.Equal(t, 0, cw.depth)
 	})
 
 	t.Run("wrap context", func(t *testing.T) {
 		adapter = &testAdapter{result: 1}
-		cw = wrapContext(ctx, adapter)
+		cw = wrapContext(ctx, contextData{adapter: adapter, depth: 1, options: TransactionOptions{ReadOnly: true}})
 		ctx = cw.ctx
 
 		assert.Equal(t, ctx, cw.ctx)
 		assert.Equal(t, adapter, cw.adapter)
+		assert.Equal(t, 1, cw.depth)
+		assert.True(t, cw.options.ReadOnly)
 	})
 
 	t.Run("fetch wrapped context", func(t *testing.T) {
-		cw = fetchContext(ctx, adapter)
+		cw = fetchContext(ctx, &testAdapter{})
 		assert.Equal(t, ctx, cw.ctx)
 		assert.Equal(t, adapter, cw.adapter)
+		assert.Equal(t, 1, cw.depth)
+		assert.True(t, cw.options.ReadOnly)
 	})
 }
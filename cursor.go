@@ -108,3 +108,40 @@ func scanMulti(cur Cursor, keyField string, keyType reflect.Type, cols map[any][
 
 	return nil
 }
+
+// scanCount scans result of query grouped by key field into counts.
+func scanCount(cur Cursor, keyField string, keyType reflect.Type, counts map[any]int) error {
+	defer cur.Close()
+
+	fields, err := cur.Fields()
+	if err != nil {
+		return err
+	}
+
+	for cur.Next() {
+		var (
+			key      = reflect.New(keyType)
+			count    int
+			scanners = make([]any, len(fields))
+		)
+
+		for i, field := range fields {
+			switch field {
+			case keyField:
+				scanners[i] = Nullable(key.Interface())
+			case "count":
+				scanners[i] = Nullable(&count)
+			default:
+				scanners[i] = cur.NopScanner()
+			}
+		}
+
+		if err := cur.Scan(scanners...); err != nil {
+			return err
+		}
+
+		counts[key.Elem().Interface()] += count
+	}
+
+	return nil
+}
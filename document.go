@@ -176,7 +176,9 @@ func (d Document) Scanners(fields []string) []any {
 				ft = fv.Type()
 			)
 
-			if ft.Kind() == reflect.Ptr {
+			if d.meta.isJSON(field) {
+				result[index] = jsonValue{value: fv.Addr().Interface()}
+			} else if ft.Kind() == reflect.Ptr {
 				result[index] = fv.Addr().Interface()
 			} else {
 				result[index] = Nullable(fv.Addr().Interface())
@@ -200,9 +202,14 @@ func (d Document) Scanners(fields []string) []any {
 
 	// get scanners from associations
 	for assocName, refs := range assocRefs {
+		var assocDoc *Document
 		if assoc, ok := d.association(assocName); ok && assoc.Type() == BelongsTo || assoc.Type() == HasOne {
+			// polymorphic belongs to target is nil when its type is unknown.
+			assocDoc, _ = assoc.Document()
+		}
+
+		if assocDoc != nil {
 			var (
-				assocDoc, _   = assoc.Document()
 				assocScanners = assocDoc.Scanners(refs.fields)
 			)
 
@@ -67,6 +67,9 @@ type cachedDocumentMeta struct {
 	primaryField []string
 	primaryIndex [][]int
 	preload      []string
+	json         map[string]struct{}
+	counts       map[string]string
+	tenantField  string
 	flag         DocumentFlag
 }
 
@@ -89,6 +92,22 @@ func (cdm *cachedDocumentMeta) addFieldIndex(name string, index []int) {
 	cdm.index[name] = index
 }
 
+// Marks field as json field
+func (cdm *cachedDocumentMeta) addJSON(name string) {
+	if cdm.json == nil {
+		cdm.json = make(map[string]struct{})
+	}
+	cdm.json[name] = struct{}{}
+}
+
+// Marks field as count of the association
+func (cdm *cachedDocumentMeta) addCount(assoc string, name string) {
+	if cdm.counts == nil {
+		cdm.counts = make(map[string]string)
+	}
+	cdm.counts[assoc] = name
+}
+
 // Transfer values from other document data
 func (cdm *cachedDocumentMeta) mergeEmbedded(other cachedDocumentMeta, indexPrefix int, namePrefix string) {
 	for name, path := range other.index {
@@ -103,6 +122,15 @@ func (cdm *cachedDocumentMeta) mergeEmbedded(other cachedDocumentMeta, indexPref
 		cdm.primaryIndex = append(cdm.primaryIndex, append([]int{indexPrefix}, index...))
 	}
 	cdm.preload = appendWithPrefix(cdm.preload, other.preload, namePrefix)
+	for name := range other.json {
+		cdm.addJSON(namePrefix + name)
+	}
+	for assoc, name := range other.counts {
+		cdm.addCount(namePrefix+assoc, namePrefix+name)
+	}
+	if other.tenantField != "" {
+		cdm.tenantField = namePrefix + other.tenantField
+	}
 	cdm.flag |= other.flag
 }
 
@@ -202,6 +230,18 @@ func (dm DocumentMeta) association(name string) (AssociationMeta, bool) {
 	return getAssociationMeta(dm.rt, index), true
 }
 
+// CountField returns name of the field that stores count of the association, eg: `db:"comments_count,count:comments"`.
+func (dm DocumentMeta) CountField(assoc string) (string, bool) {
+	name, ok := dm.counts[assoc]
+	return name, ok
+}
+
+// isJSON returns true if field is tagged as json.
+func (dm DocumentMeta) isJSON(field string) bool {
+	_, ok := dm.json[field]
+	return ok
+}
+
 // Flag returns true if struct contains specified flag.
 func (dm DocumentMeta) Flag(flag DocumentFlag) bool {
 	return dm.flag.Is(flag)
@@ -234,6 +274,20 @@ func getDocumentMeta(rt reflect.Type, skipAssoc bool) DocumentMeta {
 			continue
 		}
 
+		// polymorphic belongs to association stores its target in interface field.
+		if typ.Kind() == reflect.Interface && isPolymorphic(sf) {
+			meta.addFieldIndex(name, sf.Index)
+
+			if !skipAssoc {
+				meta.belongsTo = append(meta.belongsTo, name)
+				if assocMeta := getAssociationMeta(rt, sf.Index); assocMeta.autoload {
+					meta.preload = append(meta.preload, name)
+				}
+			}
+
+			continue
+		}
+
 		for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Interface || typ.Kind() == reflect.Slice {
 			typ = typ.Elem()
 		}
@@ -250,6 +304,28 @@ func getDocumentMeta(rt reflect.Type, skipAssoc bool) DocumentMeta {
 
 		meta.addFieldIndex(name, sf.Index)
 
+		// count field is filled by PreloadCount, and never persisted.
+		if assoc := countAssoc(sf); assoc != "" {
+			meta.addCount(assoc, name)
+			continue
+		}
+
+		// computed field is only scanned from select expression, and never persisted.
+		if isComputed(sf) {
+			continue
+		}
+
+		if isTenant(sf) {
+			meta.tenantField = name
+		}
+
+		// json field is always a field, even if it's a struct with primary key.
+		if isJSON(sf) {
+			meta.fields = append(meta.fields, name)
+			meta.addJSON(name)
+			continue
+		}
+
 		if flag := extractFlag(typ, name); flag != Invalid {
 			meta.fields = append(meta.fields, name)
 			meta.flag |= flag
@@ -292,6 +368,11 @@ func getDocumentMeta(rt reflect.Type, skipAssoc bool) DocumentMeta {
 	meta.primaryField = append(meta.primaryField, primaryField...)
 	meta.primaryIndex = append(meta.primaryIndex, primaryIndex...)
 
+	registerDefaultScope(rt, meta.table)
+	if meta.tenantField != "" {
+		tenantFields.Store(meta.table, meta.tenantField)
+	}
+
 	if !skipAssoc {
 		documentMetaCache.Store(rt, meta)
 	}
@@ -368,6 +449,30 @@ func isEmbedded(sf reflect.StructField) bool {
 	return false
 }
 
+func isComputed(sf reflect.StructField) bool {
+	return strings.HasSuffix(sf.Tag.Get("db"), ",computed")
+}
+
+func countAssoc(sf reflect.StructField) string {
+	if _, option, ok := strings.Cut(sf.Tag.Get("db"), ",count:"); ok {
+		return option
+	}
+
+	return ""
+}
+
+func isJSON(sf reflect.StructField) bool {
+	return strings.HasSuffix(sf.Tag.Get("db"), ",json")
+}
+
+func isPolymorphic(sf reflect.StructField) bool {
+	return sf.Tag.Get("polymorphic") != ""
+}
+
+func isTenant(sf reflect.StructField) bool {
+	return strings.HasSuffix(sf.Tag.Get("db"), ",tenant")
+}
+
 func searchPrimary(rt reflect.Type) ([]string, [][]int) {
 	if result, cached := primariesCache.Load(rt); cached {
 		p := result.(primaryData)
@@ -34,6 +34,20 @@ func TestDocumentMeta_Association_slice(t *testing.T) {
 	assert.Equal(t, getDocumentMeta(reflect.TypeOf(Email{}), false), assocMeta.DocumentMeta())
 }
 
+func TestDocumentMeta_CountField(t *testing.T) {
+	var (
+		docMeta = getDocumentMeta(reflect.TypeOf(Author{}), false)
+	)
+
+	field, ok := docMeta.CountField("books")
+	assert.True(t, ok)
+	assert.Equal(t, "books_count", field)
+	assert.Equal(t, []string{"id"}, docMeta.Fields())
+
+	_, ok = docMeta.CountField("reviews")
+	assert.False(t, ok)
+}
+
 func TestDocumentMeta_Association_notFound(t *testing.T) {
 	var (
 		docMeta = getDocumentMeta(reflect.TypeOf(User{}), false)
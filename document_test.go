@@ -523,6 +523,43 @@ func TestDocument_Scanners(t *testing.T) {
 	assert.Equal(t, scanners, doc.Scanners(fields))
 }
 
+func TestDocument_Scanners_computed(t *testing.T) {
+	var (
+		entity = struct {
+			ID   int
+			Name string
+			Rank int `db:"rank,computed"`
+		}{}
+		doc = NewDocument(&entity)
+	)
+
+	assert.Equal(t, []string{"id", "name"}, doc.Fields())
+	assert.Equal(t, []any{Nullable(&entity.ID), Nullable(&entity.Rank)}, doc.Scanners([]string{"id", "rank"}))
+
+	_, ok := doc.Value("rank")
+	assert.True(t, ok)
+}
+
+func TestDocument_Scanners_json(t *testing.T) {
+	type Preference struct {
+		ID    int
+		Theme string
+	}
+
+	var (
+		entity = struct {
+			ID          int
+			Settings    map[string]any `db:"settings,json"`
+			Preferences []Preference   `db:"preferences,json"`
+		}{}
+		doc = NewDocument(&entity)
+	)
+
+	assert.Equal(t, []string{"id", "settings", "preferences"}, doc.Fields())
+	assert.Empty(t, doc.HasMany())
+	assert.Equal(t, []any{Nullable(&entity.ID), jsonValue{value: &entity.Settings}, jsonValue{value: &entity.Preferences}}, doc.Scanners([]string{"id", "settings", "preferences"}))
+}
+
 func TestDocument_Scanners_withAssoc(t *testing.T) {
 	var (
 		entity = Transaction{
@@ -5,6 +5,7 @@ package rel
 
 import (
 	"context"
+	"io"
 	"reflect"
 )
 
@@ -17,6 +18,18 @@ type EntityRepository[T any] interface {
 	// Limit, Offset and Sort query is automatically ignored.
 	Iterate(ctx context.Context, query Query, option ...IteratorOption) EntityIterator[T]
 
+	// IterateParallel splits iteration into partitions by range of primary field, and calls fn for each partition concurrently.
+	// Partition ranges are computed using min and max aggregate of the primary field,
+	// so entity must have a single integer primary field. Start and Finish option is overridden by the partition range.
+	// Context passed to fn is canceled as soon as any fn returns an error, and the first error is returned.
+	IterateParallel(ctx context.Context, query Query, workers int, fn func(ctx context.Context, it EntityIterator[T]) error, option ...IteratorOption) error
+
+	// Stream entities that match the query, scanning each entity lazily from the database cursor.
+	// The returned function follows range over function convention, and can be used directly in for range loop on Go 1.23 or later.
+	// Cursor is closed when iteration is finished or stopped early.
+	// Preload is not supported, ErrStreamPreload is yielded when the query preloads association.
+	Stream(ctx context.Context, queriers ...Querier) func(yield func(T, error) bool)
+
 	// Aggregate over the given field.
 	// Supported aggregate: count, sum, avg, max, min.
 	// Any select, group, offset, limit and sort query will be ignored automatically.
@@ -63,6 +76,14 @@ type EntityRepository[T any] interface {
 	// It'll panic if any error occurred.
 	MustFindAndCountAll(ctx context.Context, queriers ...Querier) ([]T, int)
 
+	// FindPage of entities using keyset pagination.
+	// Page position is specified using After or Before cursor, and size is specified using PageSize.
+	FindPage(ctx context.Context, queriers ...Querier) ([]T, Page, error)
+
+	// MustFindPage of entities using keyset pagination.
+	// It'll panic if any error occurred.
+	MustFindPage(ctx context.Context, queriers ...Querier) ([]T, Page)
+
 	// Insert a entity to database.
 	Insert(ctx context.Context, entity *T, mutators ...Mutator) error
 
@@ -121,9 +142,25 @@ type EntityRepository[T any] interface {
 	// It'll panic if any error occurred.
 	MustPreloadAll(ctx context.Context, entities *[]T, field string, queriers ...Querier)
 
+	// PreloadCount counts association without loading it.
+	// The count is assigned to field tagged with the association name, eg: `db:"comments_count,count:comments"`.
+	PreloadCount(ctx context.Context, entity *T, field string, queriers ...Querier) error
+
+	// MustPreloadCount counts association without loading it.
+	// It'll panic if any error occurred.
+	MustPreloadCount(ctx context.Context, entity *T, field string, queriers ...Querier)
+
+	// PreloadCountAll counts association of entities without loading it.
+	// The count is assigned to field tagged with the association name, eg: `db:"comments_count,count:comments"`.
+	PreloadCountAll(ctx context.Context, entities *[]T, field string, queriers ...Querier) error
+
+	// MustPreloadCountAll counts association of entities without loading it.
+	// It'll panic if any error occurred.
+	MustPreloadCountAll(ctx context.Context, entities *[]T, field string, queriers ...Querier)
+
 	// Transaction performs transaction with given function argument.
 	// Transaction scope/connection is automatically passed using context.
-	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	Transaction(ctx context.Context, fn func(ctx context.Context) error, options ...TransactionOption) error
 }
 
 type entityRepository[T any] struct {
@@ -143,11 +180,43 @@ func (er entityRepository[T]) Iterate(ctx context.Context, query Query, option .
 	return newEntityIterator[T](er.repository.Iterate(ctx, query, option...))
 }
 
+func (er entityRepository[T]) IterateParallel(ctx context.Context, query Query, workers int, fn func(ctx context.Context, it EntityIterator[T]) error, option ...IteratorOption) error {
+	if query.Table == "" {
+		var entity T
+		query.Table = getDocumentMeta(reflect.TypeOf(entity), true).Table()
+	}
+
+	return er.repository.IterateParallel(ctx, query, workers, func(ctx context.Context, it Iterator) error {
+		return fn(ctx, newEntityIterator[T](it))
+	}, option...)
+}
+
+func (er entityRepository[T]) Stream(ctx context.Context, queriers ...Querier) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		var (
+			entity       T
+			documentMeta = getDocumentMeta(reflect.TypeOf(entity), true)
+			it           = er.repository.Stream(ctx, Build(documentMeta.Table(), queriers...))
+		)
+
+		defer it.Close()
+
+		for {
+			var entity T
+			if err := it.Next(&entity); err == io.EOF {
+				return
+			} else if !yield(entity, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
 func (er entityRepository[T]) Aggregate(ctx context.Context, aggregate string, field string, queriers ...Querier) (int, error) {
 	var (
 		entity       T
 		documentMeta = getDocumentMeta(reflect.TypeOf(entity), true)
-		query        = Build(documentMeta.table, queriers...)
+		query        = Build(documentMeta.table, queriers...).Populate(documentMeta)
 	)
 
 	return er.repository.Aggregate(ctx, query, aggregate, field)
@@ -208,6 +277,18 @@ func (er entityRepository[T]) MustFindAndCountAll(ctx context.Context, queriers
 	return entities, count
 }
 
+func (er entityRepository[T]) FindPage(ctx context.Context, queriers ...Querier) ([]T, Page, error) {
+	var entities []T
+	page, err := er.repository.FindPage(ctx, &entities, queriers...)
+	return entities, page, err
+}
+
+func (er entityRepository[T]) MustFindPage(ctx context.Context, queriers ...Querier) ([]T, Page) {
+	entities, page, err := er.FindPage(ctx, queriers...)
+	must(err)
+	return entities, page
+}
+
 func (er entityRepository[T]) Insert(ctx context.Context, entity *T, mutators ...Mutator) error {
 	return er.repository.Insert(ctx, entity, mutators...)
 }
@@ -264,8 +345,24 @@ func (er entityRepository[T]) MustPreloadAll(ctx context.Context, entities *[]T,
 	er.repository.MustPreload(ctx, entities, field, queriers...)
 }
 
-func (er entityRepository[T]) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return er.repository.Transaction(ctx, fn)
+func (er entityRepository[T]) PreloadCount(ctx context.Context, entity *T, field string, queriers ...Querier) error {
+	return er.repository.PreloadCount(ctx, entity, field, queriers...)
+}
+
+func (er entityRepository[T]) MustPreloadCount(ctx context.Context, entity *T, field string, queriers ...Querier) {
+	er.repository.MustPreloadCount(ctx, entity, field, queriers...)
+}
+
+func (er entityRepository[T]) PreloadCountAll(ctx context.Context, entities *[]T, field string, queriers ...Querier) error {
+	return er.repository.PreloadCount(ctx, entities, field, queriers...)
+}
+
+func (er entityRepository[T]) MustPreloadCountAll(ctx context.Context, entities *[]T, field string, queriers ...Querier) {
+	er.repository.MustPreloadCount(ctx, entities, field, queriers...)
+}
+
+func (er entityRepository[T]) Transaction(ctx context.Context, fn func(ctx context.Context) error, options ...TransactionOption) error {
+	return er.repository.Transaction(ctx, fn, options...)
 }
 
 func NewEntityRepository[T any](repository Repository) EntityRepository[T] {
@@ -5,6 +5,8 @@ package rel
 
 import (
 	"context"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,6 +32,16 @@ func (tr *testRepository) Iterate(ctx context.Context, query Query, option ...It
 	return args.Get(0).(Iterator)
 }
 
+func (tr *testRepository) IterateParallel(ctx context.Context, query Query, workers int, fn func(ctx context.Context, it Iterator) error, option ...IteratorOption) error {
+	args := tr.Called(query, workers, option)
+	return fn(ctx, args.Get(0).(Iterator))
+}
+
+func (tr *testRepository) Stream(ctx context.Context, query Query) Iterator {
+	args := tr.Called(query)
+	return args.Get(0).(Iterator)
+}
+
 func (tr *testRepository) Aggregate(ctx context.Context, query Query, aggregate string, field string) (int, error) {
 	args := tr.Called(query, aggregate, field)
 	return args.Int(0), args.Error(1)
@@ -78,6 +90,16 @@ func (tr *testRepository) MustFindAndCountAll(ctx context.Context, entities any,
 	return args.Int(0)
 }
 
+func (tr *testRepository) FindPage(ctx context.Context, entities any, queriers ...Querier) (Page, error) {
+	args := tr.Called(entities, queriers)
+	return args.Get(0).(Page), args.Error(1)
+}
+
+func (tr *testRepository) MustFindPage(ctx context.Context, entities any, queriers ...Querier) Page {
+	args := tr.Called(entities, queriers)
+	return args.Get(0).(Page)
+}
+
 func (tr *testRepository) Insert(ctx context.Context, entity any, mutators ...Mutator) error {
 	args := tr.Called(entity, mutators)
 	return args.Error(0)
@@ -152,6 +174,15 @@ func (tr *testRepository) MustPreload(ctx context.Context, entities any, field s
 	tr.Called(entities, field, queriers)
 }
 
+func (tr *testRepository) PreloadCount(ctx context.Context, entities any, field string, queriers ...Querier) error {
+	args := tr.Called(entities, field, queriers)
+	return args.Error(0)
+}
+
+func (tr *testRepository) MustPreloadCount(ctx context.Context, entities any, field string, queriers ...Querier) {
+	tr.Called(entities, field, queriers)
+}
+
 func (tr *testRepository) Exec(ctx context.Context, statement string, arg ...any) (int, int, error) {
 	args := tr.Called(statement, statement, arg)
 	return args.Int(0), args.Int(1), args.Error(2)
@@ -162,8 +193,8 @@ func (tr *testRepository) MustExec(ctx context.Context, statement string, arg ..
 	return args.Int(0), args.Int(1)
 }
 
-func (tr *testRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	tr.Called()
+func (tr *testRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error, options ...TransactionOption) error {
+	tr.Called(options)
 	return fn(ctx)
 }
 
@@ -192,6 +223,95 @@ func TestEntityRepository_Iterate(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestEntityRepository_IterateParallel(t *testing.T) {
+	var (
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+		iterator   = &testIterator{}
+	)
+
+	repo.On("IterateParallel", From("users"), 2, []IteratorOption{BatchSize(10)}).Return(iterator)
+	iterator.On("Next", mock.Anything).Return(io.EOF).Once()
+
+	err := entityRepo.IterateParallel(context.TODO(), Where(), 2, func(ctx context.Context, it EntityIterator[User]) error {
+		_, err := it.Next()
+		assert.Equal(t, io.EOF, err)
+		return nil
+	}, BatchSize(10))
+
+	assert.Nil(t, err)
+	repo.AssertExpectations(t)
+	iterator.AssertExpectations(t)
+}
+
+func TestEntityRepository_Stream(t *testing.T) {
+	var (
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+		iterator   = &testIterator{}
+		users      []User
+	)
+
+	repo.On("Stream", From("users").Where(Eq("status", "pending"))).Return(iterator)
+	iterator.On("Next", mock.Anything).Return(nil).Twice()
+	iterator.On("Next", mock.Anything).Return(io.EOF).Once()
+	iterator.On("Close").Return(nil).Once()
+
+	entityRepo.Stream(context.TODO(), Where(Eq("status", "pending")))(func(user User, err error) bool {
+		assert.Nil(t, err)
+		users = append(users, user)
+		return true
+	})
+
+	assert.Len(t, users, 2)
+	repo.AssertExpectations(t)
+	iterator.AssertExpectations(t)
+}
+
+func TestEntityRepository_Stream_stop(t *testing.T) {
+	var (
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+		iterator   = &testIterator{}
+		count      = 0
+	)
+
+	repo.On("Stream", From("users")).Return(iterator)
+	iterator.On("Next", mock.Anything).Return(nil).Once()
+	iterator.On("Close").Return(nil).Once()
+
+	entityRepo.Stream(context.TODO())(func(user User, err error) bool {
+		count++
+		return false
+	})
+
+	assert.Equal(t, 1, count)
+	repo.AssertExpectations(t)
+	iterator.AssertExpectations(t)
+}
+
+func TestEntityRepository_Stream_error(t *testing.T) {
+	var (
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+		iterator   = &testIterator{}
+		errs       []error
+	)
+
+	repo.On("Stream", From("users")).Return(iterator)
+	iterator.On("Next", mock.Anything).Return(errors.New("error")).Once()
+	iterator.On("Close").Return(nil).Once()
+
+	entityRepo.Stream(context.TODO())(func(user User, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+
+	assert.Equal(t, []error{errors.New("error")}, errs)
+	repo.AssertExpectations(t)
+	iterator.AssertExpectations(t)
+}
+
 func TestEntityRepository_Aggregate(t *testing.T) {
 	var (
 		repo       = &testRepository{}
@@ -351,6 +471,43 @@ func TestEntityRepository_MustFindAndCountAll(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestEntityRepository_FindPage(t *testing.T) {
+	var (
+		users      []User
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+		query      = From("users").After("cursor").Limit(1)
+		page       = Page{Next: "next", Prev: "prev"}
+	)
+
+	repo.On("FindPage", &users, []Querier{query}).Return(page, nil)
+
+	result, resultPage, err := entityRepo.FindPage(context.TODO(), query)
+	assert.Nil(t, err)
+	assert.Equal(t, page, resultPage)
+	assert.Equal(t, users, result)
+
+	repo.AssertExpectations(t)
+}
+
+func TestEntityRepository_MustFindPage(t *testing.T) {
+	var (
+		users      []User
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+		query      = From("users").Limit(1)
+		page       = Page{Next: "next"}
+	)
+
+	repo.On("FindPage", &users, []Querier{query}).Return(page, nil)
+
+	result, resultPage := entityRepo.MustFindPage(context.TODO(), query)
+	assert.Equal(t, page, resultPage)
+	assert.Equal(t, users, result)
+
+	repo.AssertExpectations(t)
+}
+
 func TestEntityRepository_Insert(t *testing.T) {
 	var (
 		user       User
@@ -554,17 +711,75 @@ func TestEntityRepository_MustPreloadAll(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestEntityRepository_PreloadCount(t *testing.T) {
+	var (
+		user       User
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+	)
+
+	repo.On("PreloadCount", &user, "transactions", []Querier(nil)).Return(nil)
+
+	err := entityRepo.PreloadCount(context.TODO(), &user, "transactions")
+	assert.Nil(t, err)
+
+	repo.AssertExpectations(t)
+}
+
+func TestEntityRepository_MustPreloadCount(t *testing.T) {
+	var (
+		user       User
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+	)
+
+	repo.On("MustPreloadCount", &user, "transactions", []Querier(nil))
+
+	entityRepo.MustPreloadCount(context.TODO(), &user, "transactions")
+
+	repo.AssertExpectations(t)
+}
+
+func TestEntityRepository_PreloadCountAll(t *testing.T) {
+	var (
+		users      []User
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+	)
+
+	repo.On("PreloadCount", &users, "transactions", []Querier(nil)).Return(nil)
+
+	err := entityRepo.PreloadCountAll(context.TODO(), &users, "transactions")
+	assert.Nil(t, err)
+
+	repo.AssertExpectations(t)
+}
+
+func TestEntityRepository_MustPreloadCountAll(t *testing.T) {
+	var (
+		users      []User
+		repo       = &testRepository{}
+		entityRepo = NewEntityRepository[User](repo)
+	)
+
+	repo.On("MustPreloadCount", &users, "transactions", []Querier(nil))
+
+	entityRepo.MustPreloadCountAll(context.TODO(), &users, "transactions")
+
+	repo.AssertExpectations(t)
+}
+
 func TestEntityRepository_Transaction(t *testing.T) {
 	var (
 		repo       = &testRepository{}
 		entityRepo = NewEntityRepository[User](repo)
 	)
 
-	repo.On("Transaction")
+	repo.On("Transaction", []TransactionOption{ReadOnly()})
 
 	err := entityRepo.Transaction(context.TODO(), func(ctx context.Context) error {
 		return nil
-	})
+	}, ReadOnly())
 
 	assert.Nil(t, err)
 
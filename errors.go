@@ -28,6 +28,18 @@ var (
 	// ErrForeignKeyConstraint is an auxiliary variable for error handling.
 	// This is only to be used when checking error with errors.Is(err, ErrForeignKeyConstraint).
 	ErrForeignKeyConstraint = ConstraintError{Type: ForeignKeyConstraint}
+
+	// ErrSerialization is an auxiliary variable for error handling.
+	// This is only to be used when checking error with errors.Is(err, ErrSerialization).
+	ErrSerialization = TransactionError{Type: SerializationFailure}
+
+	// ErrDeadlock is an auxiliary variable for error handling.
+	// This is only to be used when checking error with errors.Is(err, ErrDeadlock).
+	ErrDeadlock = TransactionError{Type: Deadlock}
+
+	// ErrUnsupportedFilter is an auxiliary variable for error handling.
+	// This is only to be used when checking error with errors.Is(err, ErrUnsupportedFilter).
+	ErrUnsupportedFilter = UnsupportedFilterError{}
 )
 
 // NotFoundError returned whenever Find returns no result.
@@ -106,3 +118,93 @@ func (ce ConstraintError) Error() string {
 
 	return ce.Type.String() + "Error"
 }
+
+// TransactionErrorType defines the type of transaction error.
+type TransactionErrorType int8
+
+const (
+	// SerializationFailure error type.
+	SerializationFailure TransactionErrorType = iota
+	// Deadlock error type.
+	Deadlock
+)
+
+// String representation of the transaction error type.
+func (tt TransactionErrorType) String() string {
+	switch tt {
+	case SerializationFailure:
+		return "SerializationFailure"
+	case Deadlock:
+		return "Deadlock"
+	default:
+		return ""
+	}
+}
+
+// TransactionError returned by adapter whenever transaction failed because of concurrent transaction.
+// Transaction that failed with this error is safe to be retried.
+type TransactionError struct {
+	Type TransactionErrorType
+	Err  error
+}
+
+// Is returns true when target error have the same type.
+func (te TransactionError) Is(target error) bool {
+	if err, ok := target.(TransactionError); ok {
+		return te.Type == err.Type
+	}
+
+	return false
+}
+
+// Unwrap internal error returned by database driver.
+func (te TransactionError) Unwrap() error {
+	return te.Err
+}
+
+// Error message.
+func (te TransactionError) Error() string {
+	if te.Err != nil {
+		return te.Type.String() + "Error: " + te.Err.Error()
+	}
+
+	return te.Type.String() + "Error"
+}
+
+// UnsupportedFilterError returned by adapter when filter operator is not supported by the database,
+// instead of generating invalid query.
+type UnsupportedFilterError struct {
+	Op  FilterOp
+	Err error
+}
+
+// Is returns true when target error is UnsupportedFilterError, use errors.As to check the operator.
+func (ufe UnsupportedFilterError) Is(target error) bool {
+	_, ok := target.(UnsupportedFilterError)
+	return ok
+}
+
+// Unwrap internal error returned by adapter.
+func (ufe UnsupportedFilterError) Unwrap() error {
+	return ufe.Err
+}
+
+// Error message.
+func (ufe UnsupportedFilterError) Error() string {
+	if ufe.Err != nil {
+		return "UnsupportedFilterError: " + ufe.Op.String() + ": " + ufe.Err.Error()
+	}
+
+	return "UnsupportedFilterError: " + ufe.Op.String()
+}
+
+// UnknownPolymorphicTypeError returned when preloading polymorphic belongs to association
+// whose type value is not registered using RegisterPolymorphic.
+type UnknownPolymorphicTypeError struct {
+	Type string
+}
+
+// Error message.
+func (upte UnknownPolymorphicTypeError) Error() string {
+	return "rel: polymorphic type (" + upte.Type + ") is not registered"
+}
@@ -3,6 +3,7 @@ package rel
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -135,3 +136,78 @@ func TestConstraintError_ErrorsIs(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionErrorType(t *testing.T) {
+	assert.Equal(t, "SerializationFailure", SerializationFailure.String())
+	assert.Equal(t, "Deadlock", Deadlock.String())
+	assert.Equal(t, "", TransactionErrorType(100).String())
+}
+
+func TestTransactionError(t *testing.T) {
+	err := TransactionError{Type: Deadlock, Err: errors.New("deadlock detected")}
+	assert.NotNil(t, err.Unwrap())
+	assert.Equal(t, "DeadlockError: deadlock detected", err.Error())
+
+	err = TransactionError{Type: SerializationFailure}
+	assert.Nil(t, err.Unwrap())
+	assert.Equal(t, "SerializationFailureError", err.Error())
+}
+
+func TestTransactionError_ErrorsIs(t *testing.T) {
+	tests := []struct {
+		err    error
+		target error
+		equal  bool
+	}{
+		{
+			err:    TransactionError{Type: SerializationFailure, Err: errors.New("could not serialize access")},
+			target: ErrSerialization,
+			equal:  true,
+		},
+		{
+			err:    TransactionError{Type: Deadlock},
+			target: ErrDeadlock,
+			equal:  true,
+		},
+		{
+			err:    TransactionError{Type: Deadlock},
+			target: ErrSerialization,
+			equal:  false,
+		},
+		{
+			err:    TransactionError{Type: Deadlock},
+			target: ErrNotFound,
+			equal:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			assert.Equal(t, test.equal, errors.Is(test.err, test.target))
+		})
+	}
+}
+
+func TestUnsupportedFilterError(t *testing.T) {
+	err := UnsupportedFilterError{Op: FilterRegexpOp, Err: errors.New("not supported")}
+	assert.NotNil(t, err.Unwrap())
+	assert.Equal(t, "UnsupportedFilterError: Regexp: not supported", err.Error())
+
+	err = UnsupportedFilterError{Op: FilterContainsOp}
+	assert.Nil(t, err.Unwrap())
+	assert.Equal(t, "UnsupportedFilterError: Contains", err.Error())
+}
+
+func TestUnsupportedFilterError_ErrorsIs(t *testing.T) {
+	var (
+		internal = errors.New("not supported")
+		err      = fmt.Errorf("adapter: %w", UnsupportedFilterError{Op: FilterILikeOp, Err: internal})
+		target   UnsupportedFilterError
+	)
+
+	assert.True(t, errors.Is(err, ErrUnsupportedFilter))
+	assert.True(t, errors.Is(err, internal))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, FilterILikeOp, target.Op)
+}
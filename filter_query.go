@@ -26,6 +26,22 @@ func (fo FilterOp) String() string {
 		"Like",
 		"NotLike",
 		"Fragment",
+		"Exists",
+		"NotExists",
+		"Between",
+		"NotBetween",
+		"ILike",
+		"NotILike",
+		"Regexp",
+		"NotRegexp",
+		"Contains",
+		"ContainedBy",
+		"Overlaps",
+		"JSONEq",
+		"JSONNe",
+		"JSONHasKey",
+		"JSONNotHasKey",
+		"Match",
 	}[fo]
 }
 
@@ -68,6 +84,46 @@ const (
 
 	// FilterFragmentOp is filter type for custom filter.
 	FilterFragmentOp
+
+	// FilterExistsOp is filter type for existence of sub query result.
+	FilterExistsOp
+	// FilterNotExistsOp is filter type for non existence of sub query result.
+	FilterNotExistsOp
+
+	// FilterBetweenOp is filter type for inclusive range comparison.
+	FilterBetweenOp
+	// FilterNotBetweenOp is filter type for not in inclusive range comparison.
+	FilterNotBetweenOp
+
+	// FilterILikeOp is filter type for case insensitive like comparison.
+	FilterILikeOp
+	// FilterNotILikeOp is filter type for case insensitive not like comparison.
+	FilterNotILikeOp
+
+	// FilterRegexpOp is filter type for regular expression match.
+	FilterRegexpOp
+	// FilterNotRegexpOp is filter type for regular expression mismatch.
+	FilterNotRegexpOp
+
+	// FilterContainsOp is filter type for array or json containment, field contains all of the value.
+	FilterContainsOp
+	// FilterContainedByOp is filter type for array or json containment, field is contained by the value.
+	FilterContainedByOp
+	// FilterOverlapsOp is filter type for array overlap, field and value have any element in common.
+	FilterOverlapsOp
+
+	// FilterJSONEqOp is filter type for equal comparison of value at json path.
+	FilterJSONEqOp
+	// FilterJSONNeOp is filter type for not equal comparison of value at json path.
+	FilterJSONNeOp
+
+	// FilterJSONHasKeyOp is filter type for existence of json path.
+	FilterJSONHasKeyOp
+	// FilterJSONNotHasKeyOp is filter type for non existence of json path.
+	FilterJSONNotHasKeyOp
+
+	// FilterMatchOp is filter type for full text search.
+	FilterMatchOp
 )
 
 // FilterQuery defines details of a condition type.
@@ -98,6 +154,25 @@ func (fq FilterQuery) String() string {
 
 	var builder strings.Builder
 	builder.WriteString("where.")
+
+	if (fq.Type == FilterExistsOp || fq.Type == FilterNotExistsOp) && fq.Field != "" {
+		if fq.Type == FilterNotExistsOp {
+			builder.WriteString("Not")
+		}
+
+		builder.WriteString("HasAssoc(\"")
+		builder.WriteString(fq.Field)
+		builder.WriteByte('"')
+
+		if filter, _ := fq.Value.(FilterQuery); !filter.None() {
+			builder.WriteString(", ")
+			builder.WriteString(filter.String())
+		}
+
+		builder.WriteByte(')')
+		return builder.String()
+	}
+
 	builder.WriteString(fq.Type.String())
 	builder.WriteByte('(')
 
@@ -110,7 +185,9 @@ func (fq FilterQuery) String() string {
 
 			builder.WriteString(fq.Inner[i].String())
 		}
-	case FilterEqOp, FilterNeOp, FilterLtOp, FilterLteOp, FilterGtOp, FilterGteOp:
+	case FilterEqOp, FilterNeOp, FilterLtOp, FilterLteOp, FilterGtOp, FilterGteOp,
+		FilterILikeOp, FilterNotILikeOp, FilterRegexpOp, FilterNotRegexpOp,
+		FilterContainsOp, FilterContainedByOp, FilterOverlapsOp, FilterJSONHasKeyOp, FilterJSONNotHasKeyOp:
 		builder.WriteByte('"')
 		builder.WriteString(fq.Field)
 		builder.WriteString("\", ")
@@ -119,11 +196,22 @@ func (fq FilterQuery) String() string {
 		builder.WriteByte('"')
 		builder.WriteString(fq.Field)
 		builder.WriteByte('"')
-	case FilterInOp, FilterNinOp:
+	case FilterInOp, FilterNinOp, FilterBetweenOp, FilterNotBetweenOp, FilterJSONEqOp, FilterJSONNeOp:
 		builder.WriteByte('"')
 		builder.WriteString(fq.Field)
 		builder.WriteString("\", ")
 		builder.WriteString(fmtAnys(fq.Value.([]any)))
+	case FilterExistsOp, FilterNotExistsOp:
+		if query, ok := fq.Value.(Query); ok {
+			builder.WriteString(query.String())
+		}
+	case FilterMatchOp:
+		if me, ok := fq.Value.(MatchExpr); ok {
+			builder.WriteString(fmtFields(me.Fields))
+			builder.WriteString(", \"")
+			builder.WriteString(me.Query)
+			builder.WriteByte('"')
+		}
 	case FilterFragmentOp:
 		v := fq.Value.([]any)
 		builder.WriteByte('"')
@@ -251,6 +339,76 @@ func (fq FilterQuery) AndNotLike(field string, pattern string) FilterQuery {
 	return fq.and(NotLike(field, pattern))
 }
 
+// AndBetween append between expression using and.
+func (fq FilterQuery) AndBetween(field string, lower any, upper any) FilterQuery {
+	return fq.and(Between(field, lower, upper))
+}
+
+// AndNotBetween append not between expression using and.
+func (fq FilterQuery) AndNotBetween(field string, lower any, upper any) FilterQuery {
+	return fq.and(NotBetween(field, lower, upper))
+}
+
+// AndILike append case insensitive like expression using and.
+func (fq FilterQuery) AndILike(field string, pattern string) FilterQuery {
+	return fq.and(ILike(field, pattern))
+}
+
+// AndNotILike append case insensitive not like expression using and.
+func (fq FilterQuery) AndNotILike(field string, pattern string) FilterQuery {
+	return fq.and(NotILike(field, pattern))
+}
+
+// AndRegexp append regular expression match expression using and.
+func (fq FilterQuery) AndRegexp(field string, pattern string) FilterQuery {
+	return fq.and(Regexp(field, pattern))
+}
+
+// AndNotRegexp append regular expression mismatch expression using and.
+func (fq FilterQuery) AndNotRegexp(field string, pattern string) FilterQuery {
+	return fq.and(NotRegexp(field, pattern))
+}
+
+// AndContains append contains expression using and.
+func (fq FilterQuery) AndContains(field string, value any) FilterQuery {
+	return fq.and(Contains(field, value))
+}
+
+// AndContainedBy append contained by expression using and.
+func (fq FilterQuery) AndContainedBy(field string, value any) FilterQuery {
+	return fq.and(ContainedBy(field, value))
+}
+
+// AndOverlaps append overlaps expression using and.
+func (fq FilterQuery) AndOverlaps(field string, value any) FilterQuery {
+	return fq.and(Overlaps(field, value))
+}
+
+// AndJSONEq append json path equal expression using and.
+func (fq FilterQuery) AndJSONEq(field string, path string, value any) FilterQuery {
+	return fq.and(JSONEq(field, path, value))
+}
+
+// AndJSONNe append json path not equal expression using and.
+func (fq FilterQuery) AndJSONNe(field string, path string, value any) FilterQuery {
+	return fq.and(JSONNe(field, path, value))
+}
+
+// AndJSONHasKey append json path exists expression using and.
+func (fq FilterQuery) AndJSONHasKey(field string, path string) FilterQuery {
+	return fq.and(JSONHasKey(field, path))
+}
+
+// AndJSONNotHasKey append json path not exists expression using and.
+func (fq FilterQuery) AndJSONNotHasKey(field string, path string) FilterQuery {
+	return fq.and(JSONNotHasKey(field, path))
+}
+
+// AndMatch append full text search expression using and.
+func (fq FilterQuery) AndMatch(fields []string, query string) FilterQuery {
+	return fq.and(Match(fields, query))
+}
+
 // AndFragment append fragment using and.
 func (fq FilterQuery) AndFragment(expr string, values ...any) FilterQuery {
 	return fq.and(FilterFragment(expr, values...))
@@ -316,6 +474,76 @@ func (fq FilterQuery) OrNotLike(field string, pattern string) FilterQuery {
 	return fq.or(NotLike(field, pattern))
 }
 
+// OrBetween append between expression using or.
+func (fq FilterQuery) OrBetween(field string, lower any, upper any) FilterQuery {
+	return fq.or(Between(field, lower, upper))
+}
+
+// OrNotBetween append not between expression using or.
+func (fq FilterQuery) OrNotBetween(field string, lower any, upper any) FilterQuery {
+	return fq.or(NotBetween(field, lower, upper))
+}
+
+// OrILike append case insensitive like expression using or.
+func (fq FilterQuery) OrILike(field string, pattern string) FilterQuery {
+	return fq.or(ILike(field, pattern))
+}
+
+// OrNotILike append case insensitive not like expression using or.
+func (fq FilterQuery) OrNotILike(field string, pattern string) FilterQuery {
+	return fq.or(NotILike(field, pattern))
+}
+
+// OrRegexp append regular expression match expression using or.
+func (fq FilterQuery) OrRegexp(field string, pattern string) FilterQuery {
+	return fq.or(Regexp(field, pattern))
+}
+
+// OrNotRegexp append regular expression mismatch expression using or.
+func (fq FilterQuery) OrNotRegexp(field string, pattern string) FilterQuery {
+	return fq.or(NotRegexp(field, pattern))
+}
+
+// OrContains append contains expression using or.
+func (fq FilterQuery) OrContains(field string, value any) FilterQuery {
+	return fq.or(Contains(field, value))
+}
+
+// OrContainedBy append contained by expression using or.
+func (fq FilterQuery) OrContainedBy(field string, value any) FilterQuery {
+	return fq.or(ContainedBy(field, value))
+}
+
+// OrOverlaps append overlaps expression using or.
+func (fq FilterQuery) OrOverlaps(field string, value any) FilterQuery {
+	return fq.or(Overlaps(field, value))
+}
+
+// OrJSONEq append json path equal expression using or.
+func (fq FilterQuery) OrJSONEq(field string, path string, value any) FilterQuery {
+	return fq.or(JSONEq(field, path, value))
+}
+
+// OrJSONNe append json path not equal expression using or.
+func (fq FilterQuery) OrJSONNe(field string, path string, value any) FilterQuery {
+	return fq.or(JSONNe(field, path, value))
+}
+
+// OrJSONHasKey append json path exists expression using or.
+func (fq FilterQuery) OrJSONHasKey(field string, path string) FilterQuery {
+	return fq.or(JSONHasKey(field, path))
+}
+
+// OrJSONNotHasKey append json path not exists expression using or.
+func (fq FilterQuery) OrJSONNotHasKey(field string, path string) FilterQuery {
+	return fq.or(JSONNotHasKey(field, path))
+}
+
+// OrMatch append full text search expression using or.
+func (fq FilterQuery) OrMatch(fields []string, query string) FilterQuery {
+	return fq.or(Match(fields, query))
+}
+
 // OrFragment append fragment using or.
 func (fq FilterQuery) OrFragment(expr string, values ...any) FilterQuery {
 	return fq.or(FilterFragment(expr, values...))
@@ -368,6 +596,30 @@ func Not(inner ...FilterQuery) FilterQuery {
 			fq.Type = FilterNinOp
 		case FilterLikeOp:
 			fq.Type = FilterNotLikeOp
+		case FilterExistsOp:
+			fq.Type = FilterNotExistsOp
+		case FilterNotExistsOp:
+			fq.Type = FilterExistsOp
+		case FilterBetweenOp:
+			fq.Type = FilterNotBetweenOp
+		case FilterNotBetweenOp:
+			fq.Type = FilterBetweenOp
+		case FilterILikeOp:
+			fq.Type = FilterNotILikeOp
+		case FilterNotILikeOp:
+			fq.Type = FilterILikeOp
+		case FilterRegexpOp:
+			fq.Type = FilterNotRegexpOp
+		case FilterNotRegexpOp:
+			fq.Type = FilterRegexpOp
+		case FilterJSONEqOp:
+			fq.Type = FilterJSONNeOp
+		case FilterJSONNeOp:
+			fq.Type = FilterJSONEqOp
+		case FilterJSONHasKeyOp:
+			fq.Type = FilterJSONNotHasKeyOp
+		case FilterJSONNotHasKeyOp:
+			fq.Type = FilterJSONHasKeyOp
 		default:
 			return FilterQuery{
 				Type:  FilterNotOp,
@@ -572,6 +824,134 @@ func NotLike(field string, pattern string) FilterQuery {
 	}
 }
 
+// Between compares value of field to be within lower and upper value (inclusive).
+func Between(field string, lower any, upper any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterBetweenOp,
+		Field: field,
+		Value: []any{lower, upper},
+	}
+}
+
+// NotBetween compares value of field to be outside of lower and upper value.
+func NotBetween(field string, lower any, upper any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterNotBetweenOp,
+		Field: field,
+		Value: []any{lower, upper},
+	}
+}
+
+// ILike compares value of field to match string pattern, ignoring case.
+func ILike(field string, pattern string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterILikeOp,
+		Field: field,
+		Value: pattern,
+	}
+}
+
+// NotILike compares value of field to not match string pattern, ignoring case.
+func NotILike(field string, pattern string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterNotILikeOp,
+		Field: field,
+		Value: pattern,
+	}
+}
+
+// Regexp compares value of field to match regular expression pattern.
+// Regular expression syntax may differ between database, please consult to your database documentation.
+func Regexp(field string, pattern string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterRegexpOp,
+		Field: field,
+		Value: pattern,
+	}
+}
+
+// NotRegexp compares value of field to not match regular expression pattern.
+func NotRegexp(field string, pattern string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterNotRegexpOp,
+		Field: field,
+		Value: pattern,
+	}
+}
+
+// Contains check whether array or json value of field contains all elements of the value.
+func Contains(field string, value any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterContainsOp,
+		Field: field,
+		Value: value,
+	}
+}
+
+// ContainedBy check whether all elements of array or json value of field are contained by the value.
+func ContainedBy(field string, value any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterContainedByOp,
+		Field: field,
+		Value: value,
+	}
+}
+
+// Overlaps check whether array value of field has any element in common with the value.
+func Overlaps(field string, value any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterOverlapsOp,
+		Field: field,
+		Value: value,
+	}
+}
+
+// JSONEq compares value at the path inside json field to be equal with the value.
+// Path uses json path syntax (eg: $.address.city), adapter translates it to the database dialect.
+func JSONEq(field string, path string, value any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterJSONEqOp,
+		Field: field,
+		Value: []any{path, value},
+	}
+}
+
+// JSONNe compares value at the path inside json field to be not equal with the value.
+func JSONNe(field string, path string, value any) FilterQuery {
+	return FilterQuery{
+		Type:  FilterJSONNeOp,
+		Field: field,
+		Value: []any{path, value},
+	}
+}
+
+// JSONHasKey check whether the path exists inside json field.
+func JSONHasKey(field string, path string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterJSONHasKeyOp,
+		Field: field,
+		Value: path,
+	}
+}
+
+// JSONNotHasKey check whether the path doesn't exist inside json field.
+func JSONNotHasKey(field string, path string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterJSONNotHasKeyOp,
+		Field: field,
+		Value: path,
+	}
+}
+
+// Match filters record using full text search of query against fields.
+// Adapter translates it to the native full text feature, use SortRank to order the result by relevance.
+func Match(fields []string, query string) FilterQuery {
+	return FilterQuery{
+		Type:  FilterMatchOp,
+		Value: Rank(fields, query),
+	}
+}
+
 // FilterFragment add custom filter.
 func FilterFragment(expr string, values ...any) FilterQuery {
 	return FilterQuery{
@@ -581,6 +961,121 @@ func FilterFragment(expr string, values ...any) FilterQuery {
 	}
 }
 
+// Exists check whether sub query returns any result.
+// Use Field as the value of filter inside sub query to correlate with field of the outer query.
+func Exists(sub Query) FilterQuery {
+	return FilterQuery{
+		Type:  FilterExistsOp,
+		Value: sub,
+	}
+}
+
+// NotExists check whether sub query returns no result.
+// Use Field as the value of filter inside sub query to correlate with field of the outer query.
+func NotExists(sub Query) FilterQuery {
+	return FilterQuery{
+		Type:  FilterNotExistsOp,
+		Value: sub,
+	}
+}
+
+// HasAssoc check whether entity has any association that matches the filters.
+// It's resolved into Exists filter using reference and foreign field of the association when the query is populated,
+// association table is aliased using association name, so filters can refer to its field as "assoc.field".
+// Association can only be resolved when entity is known, so it panics when used by table only operation
+// such as Count, Aggregate, UpdateAny and DeleteAny, use EntityRepository to count or aggregate by association instead.
+func HasAssoc(assoc string, filters ...FilterQuery) FilterQuery {
+	return FilterQuery{
+		Type:  FilterExistsOp,
+		Field: assoc,
+		Value: And(filters...),
+	}
+}
+
+// NotHasAssoc check whether entity has no association that matches the filters.
+func NotHasAssoc(assoc string, filters ...FilterQuery) FilterQuery {
+	return Not(HasAssoc(assoc, filters...))
+}
+
+// mustResolvedAssoc panics when filter contains association filter that is not resolved using document meta.
+func mustResolvedAssoc(fq FilterQuery) {
+	switch fq.Type {
+	case FilterAndOp, FilterOrOp, FilterNotOp:
+		for i := range fq.Inner {
+			mustResolvedAssoc(fq.Inner[i])
+		}
+	case FilterExistsOp, FilterNotExistsOp:
+		if fq.Field != "" {
+			panic("rel: has assoc filter (" + fq.Field + ") requires entity to be resolved, it can't be used by table only operation")
+		}
+	}
+}
+
+// populateAssoc resolves association filter using given document meta, the filter is copied when it's changed.
+func (fq FilterQuery) populateAssoc(docMeta DocumentMeta) (FilterQuery, bool) {
+	switch fq.Type {
+	case FilterAndOp, FilterOrOp, FilterNotOp:
+		var inner []FilterQuery
+		for i := range fq.Inner {
+			if filter, ok := fq.Inner[i].populateAssoc(docMeta); ok {
+				if inner == nil {
+					inner = append([]FilterQuery(nil), fq.Inner...)
+				}
+
+				inner[i] = filter
+			}
+		}
+
+		if inner == nil {
+			return fq, false
+		}
+
+		fq.Inner = inner
+		return fq, true
+	case FilterExistsOp, FilterNotExistsOp:
+		if fq.Field == "" {
+			return fq, false
+		}
+
+		var (
+			assocMeta = docMeta.Association(fq.Field)
+			filter, _ = fq.Value.(FilterQuery)
+		)
+
+		if assocMeta.Through() != "" {
+			panic("rel: has assoc filter is not supported for through association (" + fq.Field + ")")
+		}
+
+		if assocMeta.isPolymorphicBelongsTo() {
+			panic("rel: has assoc filter is not supported for polymorphic belongs to association (" + fq.Field + ")")
+		}
+
+		var (
+			assocDocMeta = assocMeta.DocumentMeta()
+			correlation  = Eq(fq.Field+"."+assocMeta.ForeignField(), Field(docMeta.Table()+"."+assocMeta.ReferenceField()))
+		)
+
+		if field := assocMeta.PolymorphicField(); field != "" {
+			correlation = correlation.AndEq(fq.Field+"."+field, assocMeta.PolymorphicValue())
+		}
+
+		var (
+			sub = From(assocDocMeta.Table() + " as " + fq.Field).Where(correlation)
+		)
+
+		if filter, _ = filter.populateAssoc(assocDocMeta); !filter.None() {
+			sub = sub.Where(filter)
+		}
+
+		fq.Field = ""
+		fq.Value = withSoftDeleteScope(assocDocMeta, sub)
+
+		return fq, true
+	}
+
+	return fq, false
+}
+
 func filterDocument(doc *Document) FilterQuery {
 	var (
 		pFields = doc.PrimaryFields()
@@ -659,12 +1154,21 @@ func filterBelongsTo(assoc Association) (FilterQuery, error) {
 	return filter, nil
 }
 
+// filterPolymorphic adds type filter of polymorphic has one or has many association.
+func filterPolymorphic(assoc Association, filter FilterQuery) FilterQuery {
+	if field := assoc.PolymorphicField(); field != "" {
+		filter = filter.AndEq(field, assoc.PolymorphicValue())
+	}
+
+	return filter
+}
+
 func filterHasOne(assoc Association, asssocDoc *Document) (FilterQuery, error) {
 	var (
 		fField = assoc.ForeignField()
 		fValue = assoc.ForeignValue()
 		rValue = assoc.ReferenceValue()
-		filter = filterDocument(asssocDoc).AndEq(fField, rValue)
+		filter = filterPolymorphic(assoc, filterDocument(asssocDoc).AndEq(fField, rValue))
 	)
 
 	if rValue != fValue {
@@ -1,6 +1,8 @@
 package rel
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -433,6 +435,71 @@ func TestFilterQuery_Not(t *testing.T) {
 			FilterLikeOp,
 			FilterNotLikeOp,
 		},
+		{
+			`Not Exists`,
+			FilterExistsOp,
+			FilterNotExistsOp,
+		},
+		{
+			`Not NotExists`,
+			FilterNotExistsOp,
+			FilterExistsOp,
+		},
+		{
+			`Not Between`,
+			FilterBetweenOp,
+			FilterNotBetweenOp,
+		},
+		{
+			`Not NotBetween`,
+			FilterNotBetweenOp,
+			FilterBetweenOp,
+		},
+		{
+			`Not ILike`,
+			FilterILikeOp,
+			FilterNotILikeOp,
+		},
+		{
+			`Not NotILike`,
+			FilterNotILikeOp,
+			FilterILikeOp,
+		},
+		{
+			`Not Regexp`,
+			FilterRegexpOp,
+			FilterNotRegexpOp,
+		},
+		{
+			`Not NotRegexp`,
+			FilterNotRegexpOp,
+			FilterRegexpOp,
+		},
+		{
+			`Not JSONEq`,
+			FilterJSONEqOp,
+			FilterJSONNeOp,
+		},
+		{
+			`Not JSONNe`,
+			FilterJSONNeOp,
+			FilterJSONEqOp,
+		},
+		{
+			`Not JSONHasKey`,
+			FilterJSONHasKeyOp,
+			FilterJSONNotHasKeyOp,
+		},
+		{
+			`Not JSONNotHasKey`,
+			FilterJSONNotHasKeyOp,
+			FilterJSONHasKeyOp,
+		},
+		{
+			`Not Contains`,
+			FilterContainsOp,
+			FilterNotOp,
+		},
 		{
 			`And Op`,
 			FilterAndOp,
@@ -589,6 +656,173 @@ func TestFilterQuery_AndNotLike(t *testing.T) {
 	}, FilterQuery{}.AndNotLike("field", "%expr%"))
 }
 
+func TestFilterQuery_AndBetween(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterBetweenOp,
+				Field: "field",
+				Value: []any{10, 20},
+			},
+		},
+	}, FilterQuery{}.AndBetween("field", 10, 20))
+}
+
+func TestFilterQuery_AndNotBetween(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterNotBetweenOp,
+				Field: "field",
+				Value: []any{10, 20},
+			},
+		},
+	}, FilterQuery{}.AndNotBetween("field", 10, 20))
+}
+
+func TestFilterQuery_AndILike(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterILikeOp,
+				Field: "field",
+				Value: "%expr%",
+			},
+		},
+	}, FilterQuery{}.AndILike("field", "%expr%"))
+}
+
+func TestFilterQuery_AndNotILike(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterNotILikeOp,
+				Field: "field",
+				Value: "%expr%",
+			},
+		},
+	}, FilterQuery{}.AndNotILike("field", "%expr%"))
+}
+
+func TestFilterQuery_AndRegexp(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterRegexpOp,
+				Field: "field",
+				Value: "^expr",
+			},
+		},
+	}, FilterQuery{}.AndRegexp("field", "^expr"))
+}
+
+func TestFilterQuery_AndNotRegexp(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterNotRegexpOp,
+				Field: "field",
+				Value: "^expr",
+			},
+		},
+	}, FilterQuery{}.AndNotRegexp("field", "^expr"))
+}
+
+func TestFilterQuery_AndContains(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterContainsOp,
+				Field: "field",
+				Value: []string{"a", "b"},
+			},
+		},
+	}, FilterQuery{}.AndContains("field", []string{"a", "b"}))
+}
+
+func TestFilterQuery_AndContainedBy(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterContainedByOp,
+				Field: "field",
+				Value: []string{"a", "b"},
+			},
+		},
+	}, FilterQuery{}.AndContainedBy("field", []string{"a", "b"}))
+}
+
+func TestFilterQuery_AndOverlaps(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterOverlapsOp,
+				Field: "field",
+				Value: []string{"a", "b"},
+			},
+		},
+	}, FilterQuery{}.AndOverlaps("field", []string{"a", "b"}))
+}
+
+func TestFilterQuery_AndJSONEq(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONEqOp,
+				Field: "settings",
+				Value: []any{"$.theme", "dark"},
+			},
+		},
+	}, FilterQuery{}.AndJSONEq("settings", "$.theme", "dark"))
+}
+
+func TestFilterQuery_AndJSONNe(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONNeOp,
+				Field: "settings",
+				Value: []any{"$.theme", "dark"},
+			},
+		},
+	}, FilterQuery{}.AndJSONNe("settings", "$.theme", "dark"))
+}
+
+func TestFilterQuery_AndJSONHasKey(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONHasKeyOp,
+				Field: "settings",
+				Value: "$.theme",
+			},
+		},
+	}, FilterQuery{}.AndJSONHasKey("settings", "$.theme"))
+}
+
+func TestFilterQuery_AndJSONNotHasKey(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONNotHasKeyOp,
+				Field: "settings",
+				Value: "$.theme",
+			},
+		},
+	}, FilterQuery{}.AndJSONNotHasKey("settings", "$.theme"))
+}
+
+func TestFilterQuery_AndMatch(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Inner: []FilterQuery{
+			{
+				Type:  FilterMatchOp,
+				Value: MatchExpr{Fields: []string{"title", "body"}, Query: "go orm"},
+			},
+		},
+	}, FilterQuery{}.AndMatch([]string{"title", "body"}, "go orm"))
+}
+
 func TestFilterQuery_AndFragment(t *testing.T) {
 	assert.Equal(t, FilterQuery{
 		Inner: []FilterQuery{
@@ -755,6 +989,187 @@ func TestFilterQuery_OrNotLike(t *testing.T) {
 	}, FilterQuery{}.OrNotLike("field", "%expr%"))
 }
 
+func TestFilterQuery_OrBetween(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterBetweenOp,
+				Field: "field",
+				Value: []any{10, 20},
+			},
+		},
+	}, FilterQuery{}.OrBetween("field", 10, 20))
+}
+
+func TestFilterQuery_OrNotBetween(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterNotBetweenOp,
+				Field: "field",
+				Value: []any{10, 20},
+			},
+		},
+	}, FilterQuery{}.OrNotBetween("field", 10, 20))
+}
+
+func TestFilterQuery_OrILike(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterILikeOp,
+				Field: "field",
+				Value: "%expr%",
+			},
+		},
+	}, FilterQuery{}.OrILike("field", "%expr%"))
+}
+
+func TestFilterQuery_OrNotILike(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterNotILikeOp,
+				Field: "field",
+				Value: "%expr%",
+			},
+		},
+	}, FilterQuery{}.OrNotILike("field", "%expr%"))
+}
+
+func TestFilterQuery_OrRegexp(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterRegexpOp,
+				Field: "field",
+				Value: "^expr",
+			},
+		},
+	}, FilterQuery{}.OrRegexp("field", "^expr"))
+}
+
+func TestFilterQuery_OrNotRegexp(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterNotRegexpOp,
+				Field: "field",
+				Value: "^expr",
+			},
+		},
+	}, FilterQuery{}.OrNotRegexp("field", "^expr"))
+}
+
+func TestFilterQuery_OrContains(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterContainsOp,
+				Field: "field",
+				Value: []string{"a", "b"},
+			},
+		},
+	}, FilterQuery{}.OrContains("field", []string{"a", "b"}))
+}
+
+func TestFilterQuery_OrContainedBy(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterContainedByOp,
+				Field: "field",
+				Value: []string{"a", "b"},
+			},
+		},
+	}, FilterQuery{}.OrContainedBy("field", []string{"a", "b"}))
+}
+
+func TestFilterQuery_OrOverlaps(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterOverlapsOp,
+				Field: "field",
+				Value: []string{"a", "b"},
+			},
+		},
+	}, FilterQuery{}.OrOverlaps("field", []string{"a", "b"}))
+}
+
+func TestFilterQuery_OrJSONEq(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONEqOp,
+				Field: "settings",
+				Value: []any{"$.theme", "dark"},
+			},
+		},
+	}, FilterQuery{}.OrJSONEq("settings", "$.theme", "dark"))
+}
+
+func TestFilterQuery_OrJSONNe(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONNeOp,
+				Field: "settings",
+				Value: []any{"$.theme", "dark"},
+			},
+		},
+	}, FilterQuery{}.OrJSONNe("settings", "$.theme", "dark"))
+}
+
+func TestFilterQuery_OrJSONHasKey(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONHasKeyOp,
+				Field: "settings",
+				Value: "$.theme",
+			},
+		},
+	}, FilterQuery{}.OrJSONHasKey("settings", "$.theme"))
+}
+
+func TestFilterQuery_OrJSONNotHasKey(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterJSONNotHasKeyOp,
+				Field: "settings",
+				Value: "$.theme",
+			},
+		},
+	}, FilterQuery{}.OrJSONNotHasKey("settings", "$.theme"))
+}
+
+func TestFilterQuery_OrMatch(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type: FilterOrOp,
+		Inner: []FilterQuery{
+			{
+				Type:  FilterMatchOp,
+				Value: MatchExpr{Fields: []string{"title", "body"}, Query: "go orm"},
+			},
+		},
+	}, FilterQuery{}.OrMatch([]string{"title", "body"}, "go orm"))
+}
+
 func TestFilterQuery_OrFragment(t *testing.T) {
 	assert.Equal(t, FilterQuery{
 		Type: FilterOrOp,
@@ -910,6 +1325,132 @@ func TestNotLike(t *testing.T) {
 	}, NotLike("field", "%expr%"))
 }
 
+func TestBetween(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterBetweenOp,
+		Field: "field",
+		Value: []any{10, 20},
+	}, Between("field", 10, 20))
+	assert.Equal(t, "where.Between(\"field\", 10, 20)", Between("field", 10, 20).String())
+}
+
+func TestNotBetween(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterNotBetweenOp,
+		Field: "field",
+		Value: []any{10, 20},
+	}, NotBetween("field", 10, 20))
+	assert.Equal(t, "where.NotBetween(\"field\", 10, 20)", NotBetween("field", 10, 20).String())
+}
+
+func TestILike(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterILikeOp,
+		Field: "field",
+		Value: "%expr%",
+	}, ILike("field", "%expr%"))
+	assert.Equal(t, "where.ILike(\"field\", \"%expr%\")", ILike("field", "%expr%").String())
+}
+
+func TestNotILike(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterNotILikeOp,
+		Field: "field",
+		Value: "%expr%",
+	}, NotILike("field", "%expr%"))
+	assert.Equal(t, "where.NotILike(\"field\", \"%expr%\")", NotILike("field", "%expr%").String())
+}
+
+func TestRegexp(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterRegexpOp,
+		Field: "field",
+		Value: "^expr",
+	}, Regexp("field", "^expr"))
+	assert.Equal(t, "where.Regexp(\"field\", \"^expr\")", Regexp("field", "^expr").String())
+}
+
+func TestNotRegexp(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterNotRegexpOp,
+		Field: "field",
+		Value: "^expr",
+	}, NotRegexp("field", "^expr"))
+	assert.Equal(t, "where.NotRegexp(\"field\", \"^expr\")", NotRegexp("field", "^expr").String())
+}
+
+func TestContains(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterContainsOp,
+		Field: "field",
+		Value: []string{"a", "b"},
+	}, Contains("field", []string{"a", "b"}))
+	assert.Equal(t, "where.Contains(\"field\", [a b])", Contains("field", []string{"a", "b"}).String())
+}
+
+func TestContainedBy(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterContainedByOp,
+		Field: "field",
+		Value: []string{"a", "b"},
+	}, ContainedBy("field", []string{"a", "b"}))
+	assert.Equal(t, "where.ContainedBy(\"field\", [a b])", ContainedBy("field", []string{"a", "b"}).String())
+}
+
+func TestOverlaps(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterOverlapsOp,
+		Field: "field",
+		Value: []string{"a", "b"},
+	}, Overlaps("field", []string{"a", "b"}))
+	assert.Equal(t, "where.Overlaps(\"field\", [a b])", Overlaps("field", []string{"a", "b"}).String())
+}
+
+func TestJSONEq(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterJSONEqOp,
+		Field: "settings",
+		Value: []any{"$.theme", "dark"},
+	}, JSONEq("settings", "$.theme", "dark"))
+	assert.Equal(t, "where.JSONEq(\"settings\", \"$.theme\", \"dark\")", JSONEq("settings", "$.theme", "dark").String())
+}
+
+func TestJSONNe(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterJSONNeOp,
+		Field: "settings",
+		Value: []any{"$.theme", "dark"},
+	}, JSONNe("settings", "$.theme", "dark"))
+	assert.Equal(t, "where.JSONNe(\"settings\", \"$.theme\", \"dark\")", JSONNe("settings", "$.theme", "dark").String())
+}
+
+func TestJSONHasKey(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterJSONHasKeyOp,
+		Field: "settings",
+		Value: "$.theme",
+	}, JSONHasKey("settings", "$.theme"))
+	assert.Equal(t, "where.JSONHasKey(\"settings\", \"$.theme\")", JSONHasKey("settings", "$.theme").String())
+}
+
+func TestJSONNotHasKey(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterJSONNotHasKeyOp,
+		Field: "settings",
+		Value: "$.theme",
+	}, JSONNotHasKey("settings", "$.theme"))
+	assert.Equal(t, "where.JSONNotHasKey(\"settings\", \"$.theme\")", JSONNotHasKey("settings", "$.theme").String())
+}
+
+func TestMatch(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterMatchOp,
+		Value: MatchExpr{Fields: []string{"title", "body"}, Query: "go orm"},
+	}, Match([]string{"title", "body"}, "go orm"))
+	assert.Equal(t, "where.Match([]string{\"title\", \"body\"}, \"go orm\")", Match([]string{"title", "body"}, "go orm").String())
+	assert.Equal(t, "where.Not(where.Match([]string{\"title\"}, \"go\"))", Not(Match([]string{"title"}, "go")).String())
+}
+
 func TestFilterFragment(t *testing.T) {
 	assert.Equal(t, FilterQuery{
 		Type:  FilterFragmentOp,
@@ -918,6 +1459,99 @@ func TestFilterFragment(t *testing.T) {
 	}, FilterFragment("expr", "value"))
 }
 
+func TestExists(t *testing.T) {
+	var (
+		sub = From("user_addresses").Where(Eq("user_addresses.user_id", Field("users.id")))
+	)
+
+	assert.Equal(t, FilterQuery{
+		Type:  FilterExistsOp,
+		Value: sub,
+	}, Exists(sub))
+	assert.Equal(t, "where.Exists(rel.From(\"user_addresses\").Where(where.Eq(\"user_addresses.user_id\", rel.Field(\"users.id\"))))", Exists(sub).String())
+}
+
+func TestNotExists(t *testing.T) {
+	var (
+		sub = From("user_addresses")
+	)
+
+	assert.Equal(t, FilterQuery{
+		Type:  FilterNotExistsOp,
+		Value: sub,
+	}, NotExists(sub))
+	assert.Equal(t, "where.NotExists(rel.From(\"user_addresses\"))", NotExists(sub).String())
+}
+
+func TestHasAssoc(t *testing.T) {
+	assert.Equal(t, FilterQuery{
+		Type:  FilterExistsOp,
+		Field: "transactions",
+		Value: Eq("status", "paid"),
+	}, HasAssoc("transactions", Eq("status", "paid")))
+	assert.Equal(t, "where.HasAssoc(\"transactions\", where.Eq(\"status\", \"paid\"))", HasAssoc("transactions", Eq("status", "paid")).String())
+	assert.Equal(t, "where.NotHasAssoc(\"transactions\")", NotHasAssoc("transactions").String())
+}
+
+func TestHasAssoc_populate(t *testing.T) {
+	var (
+		meta   = getDocumentMeta(reflect.TypeOf(User{}), false)
+		filter = Eq("name", "alice").And(Or(
+			HasAssoc("transactions", Eq("status", "paid"), HasAssoc("histories")),
+			NotHasAssoc("address"),
+		))
+		query = Build("users", filter).Populate(meta)
+	)
+
+	assert.Equal(t, Eq("name", "alice").And(Or(
+		Exists(From("transactions as transactions").Where(
+			Eq("transactions.user_id", Field("users.id")),
+			And(Eq("status", "paid"), Exists(From("histories as histories").Where(Eq("histories.transaction_id", Field("transactions.id"))))),
+		)),
+		NotExists(From("user_addresses as address").Where(Eq("address.user_id", Field("users.id")), Nil("deleted_at"))),
+	)), query.WhereQuery)
+
+	// original filter is not modified.
+	assert.Equal(t, "transactions", filter.Inner[1].Inner[0].Field)
+}
+
+func TestHasAssoc_populateThrough(t *testing.T) {
+	var (
+		meta = getDocumentMeta(reflect.TypeOf(User{}), false)
+	)
+
+	assert.Panics(t, func() {
+		Build("users", HasAssoc("roles")).Populate(meta)
+	})
+}
+
+func TestHasAssoc_unresolved(t *testing.T) {
+	var (
+		repo   = New(&testAdapter{})
+		filter = Eq("name", "alice").And(Not(HasAssoc("transactions")))
+	)
+
+	assert.NotPanics(t, func() {
+		mustResolvedAssoc(Build("users", filter).Populate(getDocumentMeta(reflect.TypeOf(User{}), false)).WhereQuery)
+	})
+
+	assert.PanicsWithValue(t, "rel: has assoc filter (transactions) requires entity to be resolved, it can't be used by table only operation", func() {
+		_, _ = repo.Count(context.TODO(), "users", filter)
+	})
+
+	assert.Panics(t, func() {
+		_, _ = repo.Aggregate(context.TODO(), From("users").Where(filter), "count", "*")
+	})
+
+	assert.Panics(t, func() {
+		_, _ = repo.UpdateAny(context.TODO(), From("users").Where(filter), Set("name", "bob"))
+	})
+
+	assert.Panics(t, func() {
+		_, _ = repo.DeleteAny(context.TODO(), From("users").Where(filter))
+	})
+}
+
 func TestFilterDocument(t *testing.T) {
 	var (
 		user = User{ID: 1}
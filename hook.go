@@ -0,0 +1,131 @@
+package rel
+
+import (
+	"context"
+	"reflect"
+)
+
+var (
+	rtAfterFinder = reflect.TypeOf((*AfterFinder)(nil)).Elem()
+)
+
+// BeforeInserter is implemented by entity that needs to be notified before it's inserted.
+// Returning an error will abort the insertion.
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context, mutation *Mutation) error
+}
+
+// AfterInserter is implemented by entity that needs to be notified after it's inserted.
+// Returning an error will abort the operation, and rollback the changes when running inside transaction.
+type AfterInserter interface {
+	AfterInsert(ctx context.Context, mutation *Mutation) error
+}
+
+// BeforeUpdater is implemented by entity that needs to be notified before it's updated.
+// Returning an error will abort the update.
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context, mutation *Mutation) error
+}
+
+// AfterUpdater is implemented by entity that needs to be notified after it's updated.
+// Returning an error will abort the operation, and rollback the changes when running inside transaction.
+type AfterUpdater interface {
+	AfterUpdate(ctx context.Context, mutation *Mutation) error
+}
+
+// BeforeDeleter is implemented by entity that needs to be notified before it's deleted.
+// Returning an error will abort the deletion.
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context, mutation *Mutation) error
+}
+
+// AfterDeleter is implemented by entity that needs to be notified after it's deleted.
+// Returning an error will abort the operation, and rollback the changes when running inside transaction.
+type AfterDeleter interface {
+	AfterDelete(ctx context.Context, mutation *Mutation) error
+}
+
+// AfterFinder is implemented by entity that needs to be notified after it's loaded by Find or FindAll.
+// Returning an error will abort the operation.
+type AfterFinder interface {
+	AfterFind(ctx context.Context) error
+}
+
+// hookEntity returns pointer to the underlying entity, so hooks with pointer receiver can be detected.
+func hookEntity(doc *Document) any {
+	if doc.rv.CanAddr() {
+		return doc.rv.Addr().Interface()
+	}
+
+	return doc.v
+}
+
+func beforeInsert(ctx context.Context, doc *Document, mutation *Mutation) error {
+	if h, ok := hookEntity(doc).(BeforeInserter); ok {
+		return h.BeforeInsert(ctx, mutation)
+	}
+
+	return nil
+}
+
+func afterInsert(ctx context.Context, doc *Document, mutation *Mutation) error {
+	if h, ok := hookEntity(doc).(AfterInserter); ok {
+		return h.AfterInsert(ctx, mutation)
+	}
+
+	return nil
+}
+
+func beforeUpdate(ctx context.Context, doc *Document, mutation *Mutation) error {
+	if h, ok := hookEntity(doc).(BeforeUpdater); ok {
+		return h.BeforeUpdate(ctx, mutation)
+	}
+
+	return nil
+}
+
+func afterUpdate(ctx context.Context, doc *Document, mutation *Mutation) error {
+	if h, ok := hookEntity(doc).(AfterUpdater); ok {
+		return h.AfterUpdate(ctx, mutation)
+	}
+
+	return nil
+}
+
+func beforeDelete(ctx context.Context, doc *Document, mutation *Mutation) error {
+	if h, ok := hookEntity(doc).(BeforeDeleter); ok {
+		return h.BeforeDelete(ctx, mutation)
+	}
+
+	return nil
+}
+
+func afterDelete(ctx context.Context, doc *Document, mutation *Mutation) error {
+	if h, ok := hookEntity(doc).(AfterDeleter); ok {
+		return h.AfterDelete(ctx, mutation)
+	}
+
+	return nil
+}
+
+func afterFind(ctx context.Context, doc *Document) error {
+	if h, ok := hookEntity(doc).(AfterFinder); ok {
+		return h.AfterFind(ctx)
+	}
+
+	return nil
+}
+
+func afterFindAll(ctx context.Context, col *Collection) error {
+	if !reflect.PtrTo(col.meta.rt).Implements(rtAfterFinder) {
+		return nil
+	}
+
+	for i := 0; i < col.Len(); i++ {
+		if err := afterFind(ctx, col.Get(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
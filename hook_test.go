@@ -0,0 +1,215 @@
+package rel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type HookedUser struct {
+	ID    int
+	Name  string
+	calls []string
+	err   error
+}
+
+func (hu *HookedUser) BeforeInsert(ctx context.Context, mutation *Mutation) error {
+	hu.calls = append(hu.calls, "BeforeInsert")
+	mutation.Add(Set("name", "hooked"))
+	return hu.err
+}
+
+func (hu *HookedUser) AfterInsert(ctx context.Context, mutation *Mutation) error {
+	hu.calls = append(hu.calls, "AfterInsert")
+	return nil
+}
+
+func (hu *HookedUser) BeforeUpdate(ctx context.Context, mutation *Mutation) error {
+	hu.calls = append(hu.calls, "BeforeUpdate")
+	return hu.err
+}
+
+func (hu *HookedUser) AfterUpdate(ctx context.Context, mutation *Mutation) error {
+	hu.calls = append(hu.calls, "AfterUpdate")
+	return nil
+}
+
+func (hu *HookedUser) BeforeDelete(ctx context.Context, mutation *Mutation) error {
+	hu.calls = append(hu.calls, "BeforeDelete")
+	return hu.err
+}
+
+func (hu *HookedUser) AfterDelete(ctx context.Context, mutation *Mutation) error {
+	hu.calls = append(hu.calls, "AfterDelete")
+	return nil
+}
+
+func (hu *HookedUser) AfterFind(ctx context.Context) error {
+	hu.calls = append(hu.calls, "AfterFind")
+	return hu.err
+}
+
+func TestHook_insert(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{Name: "name"}
+	)
+
+	adapter.On("Insert", From("hooked_users"), map[string]Mutate{"name": Set("name", "hooked")}, OnConflict{}).Return(1, nil).Once()
+
+	assert.Nil(t, repo.Insert(context.TODO(), &user))
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, []string{"BeforeInsert", "AfterInsert"}, user.calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_insertError(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{Name: "name", err: errors.New("error")}
+	)
+
+	assert.Equal(t, errors.New("error"), repo.Insert(context.TODO(), &user))
+	assert.Equal(t, []string{"BeforeInsert"}, user.calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_insertAll(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		users   = []HookedUser{{Name: "a"}, {Name: "b"}}
+		mutates = []map[string]Mutate{
+			{"name": Set("name", "hooked")},
+			{"name": Set("name", "hooked")},
+		}
+	)
+
+	adapter.On("InsertAll", From("hooked_users"), []string{"name"}, mutates, OnConflict{}).Return([]any{1, 2}, nil).Once()
+
+	assert.Nil(t, repo.InsertAll(context.TODO(), &users))
+	assert.Equal(t, []string{"BeforeInsert", "AfterInsert"}, users[0].calls)
+	assert.Equal(t, []string{"BeforeInsert", "AfterInsert"}, users[1].calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_update(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{ID: 1}
+		mutates = map[string]Mutate{"name": Set("name", "update")}
+	)
+
+	adapter.On("Update", From("hooked_users").Where(Eq("id", 1)), "id", mutates).Return(1, nil).Once()
+
+	assert.Nil(t, repo.Update(context.TODO(), &user, Set("name", "update")))
+	assert.Equal(t, []string{"BeforeUpdate", "AfterUpdate"}, user.calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_updateError(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{ID: 1, err: errors.New("error")}
+	)
+
+	assert.Equal(t, errors.New("error"), repo.Update(context.TODO(), &user, Set("name", "update")))
+	assert.Equal(t, []string{"BeforeUpdate"}, user.calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_delete(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{ID: 1}
+	)
+
+	adapter.On("Delete", From("hooked_users").Where(Eq("id", 1))).Return(1, nil).Once()
+
+	assert.Nil(t, repo.Delete(context.TODO(), &user))
+	assert.Equal(t, []string{"BeforeDelete", "AfterDelete"}, user.calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_deleteNotFound(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{ID: 1}
+	)
+
+	adapter.On("Delete", From("hooked_users").Where(Eq("id", 1))).Return(0, nil).Once()
+
+	assert.Equal(t, NotFoundError{}, repo.Delete(context.TODO(), &user))
+	assert.Equal(t, []string{"BeforeDelete"}, user.calls)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestHook_find(t *testing.T) {
+	var (
+		user    HookedUser
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		cur     = createCursor(1)
+	)
+
+	adapter.On("Query", From("hooked_users").Limit(1)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.Find(context.TODO(), &user))
+	assert.Equal(t, []string{"AfterFind"}, user.calls)
+	assert.False(t, cur.Next())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestHook_findAll(t *testing.T) {
+	var (
+		users   []HookedUser
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		cur     = createCursor(2)
+	)
+
+	adapter.On("Query", From("hooked_users")).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users))
+	assert.Len(t, users, 2)
+	assert.Equal(t, []string{"AfterFind"}, users[0].calls)
+	assert.Equal(t, []string{"AfterFind"}, users[1].calls)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestHook_transactionRollback(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = HookedUser{Name: "name", err: errors.New("error")}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Insert(ctx, &user)
+	})
+
+	assert.Equal(t, errors.New("error"), err)
+	adapter.AssertExpectations(t)
+}
@@ -2,8 +2,10 @@ package rel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"sync"
 )
 
 // Iterator allows iterating through all entity in database in batch.
@@ -72,6 +74,8 @@ type iterator struct {
 	batchSize int
 	current   int
 	query     Query
+	sorts     []SortQuery
+	last      []any
 	adapter   Adapter
 	cursor    Cursor
 	fields    []string
@@ -104,7 +108,13 @@ func (i *iterator) Next(entity any) error {
 	)
 
 	i.current++
-	return i.cursor.Scan(scanners...)
+	if err := i.cursor.Scan(scanners...); err != nil {
+		return err
+	}
+
+	var err error
+	i.last, err = keysetValues(doc, i.sorts)
+	return err
 }
 
 func (i *iterator) fetch(ctx context.Context, entity any) error {
@@ -114,9 +124,12 @@ func (i *iterator) fetch(ctx context.Context, entity any) error {
 		i.cursor.Close()
 	}
 
-	i.query = i.query.Limit(i.batchSize).Offset(i.current)
+	query := i.query.Limit(i.batchSize)
+	if i.current > 0 {
+		query = query.Where(keysetFilter(i.sorts, i.last, false))
+	}
 
-	cursor, err := i.adapter.Query(ctx, i.query)
+	cursor, err := i.adapter.Query(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -141,6 +154,8 @@ func (i *iterator) init(entity any) {
 		i.query.Table = doc.Table()
 	}
 
+	i.query = withTenantScope(i.ctx, doc.meta.tenantField, i.query.Populate(doc.meta))
+
 	if len(i.start) > 0 {
 		i.query = i.query.Where(filterDocumentPrimary(doc.PrimaryFields(), i.start, FilterGteOp))
 	}
@@ -149,7 +164,9 @@ func (i *iterator) init(entity any) {
 		i.query = i.query.Where(filterDocumentPrimary(doc.PrimaryFields(), i.finish, FilterLteOp))
 	}
 
-	i.query = i.query.SortAsc(doc.PrimaryFields()...)
+	i.sorts = keysetSorts(nil, doc.PrimaryFields())
+	i.query.SortQuery = i.sorts
+	i.query.OffsetQuery = 0
 }
 
 func newIterator(ctx context.Context, adapter Adapter, query Query, options []IteratorOption) Iterator {
@@ -166,3 +183,146 @@ func newIterator(ctx context.Context, adapter Adapter, query Query, options []It
 
 	return it
 }
+
+// ErrParallelTransaction returned by IterateParallel when called inside transaction,
+// since a transaction is bound to a single connection that can't serve concurrent cursors.
+var ErrParallelTransaction = errors.New("rel: parallel iteration is not supported inside transaction")
+
+// partitions holds primary field range of each partition, shared by partition iterators.
+// Ranges are computed once using the first entity passed to any partition iterator.
+type partitions struct {
+	once    sync.Once
+	ctx     context.Context
+	adapter Adapter
+	query   Query
+	count   int
+	ranges  [][2]int
+	err     error
+}
+
+func (p *partitions) init(entity any) error {
+	p.once.Do(func() {
+		var (
+			doc           = NewDocument(entity)
+			primaryFields = doc.PrimaryFields()
+			query         = p.query
+		)
+
+		if len(primaryFields) != 1 {
+			p.err = errors.New("rel: parallel iteration requires entity with a single primary field")
+			return
+		}
+
+		if query.Table == "" {
+			query.Table = doc.Table()
+		}
+
+		query = withTenantScope(p.ctx, doc.meta.tenantField, query.Populate(doc.meta))
+		query.GroupQuery = GroupQuery{}
+		query.SortQuery = nil
+		query.LimitQuery = 0
+		query.OffsetQuery = 0
+
+		lower, err := p.adapter.Aggregate(p.ctx, query, "min", primaryFields[0])
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		upper, err := p.adapter.Aggregate(p.ctx, query, "max", primaryFields[0])
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		p.ranges = partitionRanges(lower, upper, p.count)
+	})
+
+	return p.err
+}
+
+// partitionRanges splits [lower, upper] into at most n ranges of the same size (inclusive).
+func partitionRanges(lower int, upper int, n int) [][2]int {
+	var (
+		size   = (upper-lower)/n + 1
+		ranges = make([][2]int, 0, n)
+	)
+
+	for start := lower; start <= upper; start += size {
+		finish := start + size - 1
+		if finish > upper {
+			finish = upper
+		}
+
+		ranges = append(ranges, [2]int{start, finish})
+	}
+
+	return ranges
+}
+
+// partitionIterator iterates entities in a single partition using Start and Finish option.
+type partitionIterator struct {
+	index      int
+	partitions *partitions
+	options    []IteratorOption
+	iterator   Iterator
+}
+
+func (pi *partitionIterator) Close() error {
+	if pi.iterator != nil {
+		return pi.iterator.Close()
+	}
+
+	return nil
+}
+
+func (pi *partitionIterator) Next(entity any) error {
+	var (
+		p = pi.partitions
+	)
+
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	if pi.iterator == nil {
+		if err := p.init(entity); err != nil {
+			return err
+		}
+
+		if pi.index >= len(p.ranges) {
+			return io.EOF
+		}
+
+		var (
+			r       = p.ranges[pi.index]
+			options = append(pi.options[:len(pi.options):len(pi.options)], Start(r[0]), Finish(r[1]))
+		)
+
+		pi.iterator = newIterator(p.ctx, p.adapter, p.query, options)
+	}
+
+	return pi.iterator.Next(entity)
+}
+
+func newPartitionIterators(ctx context.Context, adapter Adapter, query Query, count int, options []IteratorOption) []Iterator {
+	var (
+		p = &partitions{
+			ctx:     ctx,
+			adapter: adapter,
+			query:   query,
+			count:   count,
+		}
+		iterators = make([]Iterator, count)
+	)
+
+	for i := range iterators {
+		iterators[i] = &partitionIterator{
+			index:      i,
+			partitions: p,
+			options:    options,
+		}
+	}
+
+	return iterators
+}
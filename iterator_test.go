@@ -24,8 +24,8 @@ func TestIterator(t *testing.T) {
 
 	query = query.From("users").SortAsc("id").Limit(5)
 	adapter.On("Query", query).Return(cur1, nil).Once()
-	adapter.On("Query", query.Offset(5)).Return(cur2, nil).Once()
-	adapter.On("Query", query.Offset(10)).Return(cur3, nil).Once()
+	adapter.On("Query", query.Where(Gt("id", 10))).Return(cur2, nil).Once()
+	adapter.On("Query", query.Where(Gt("id", 10))).Return(cur3, nil).Once()
 
 	entitiesCount := 0
 	for {
@@ -171,3 +171,71 @@ func TestIteratorOption_String(t *testing.T) {
 	assert.Equal(t, "rel.Finish(30, 31)", fmt.Sprint(Finish(30, 31)))
 	assert.Equal(t, "rel.Finish(\"def\")", fmt.Sprint(Finish("def")))
 }
+
+func TestPartitionRanges(t *testing.T) {
+	assert.Equal(t, [][2]int{{1, 4}, {5, 8}, {9, 10}}, partitionRanges(1, 10, 3))
+	assert.Equal(t, [][2]int{{1, 1}, {2, 2}}, partitionRanges(1, 2, 4))
+	assert.Equal(t, [][2]int{{5, 5}}, partitionRanges(5, 5, 2))
+}
+
+func TestPartitionIterator(t *testing.T) {
+	var (
+		user      User
+		adapter   = &testAdapter{}
+		query     = From("users")
+		cur       = createCursor(1)
+		iterators = newPartitionIterators(context.TODO(), adapter, query, 2, nil)
+	)
+
+	adapter.On("Aggregate", query, "min", "id").Return(1, nil).Once()
+	adapter.On("Aggregate", query, "max", "id").Return(10, nil).Once()
+	adapter.On("Query", query.Where(Gte("id", 6), Lte("id", 10)).SortAsc("id").Limit(1000)).Return(cur, nil).Once()
+
+	assert.Len(t, iterators, 2)
+	assert.Nil(t, iterators[1].Next(&user))
+	assert.Equal(t, io.EOF, iterators[1].Next(&user))
+	assert.Nil(t, iterators[1].Close())
+	assert.Nil(t, iterators[0].Close())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestPartitionIterator_aggregateError(t *testing.T) {
+	var (
+		user      User
+		adapter   = &testAdapter{}
+		query     = From("users")
+		iterators = newPartitionIterators(context.TODO(), adapter, query, 2, nil)
+		err       = errors.New("error")
+	)
+
+	adapter.On("Aggregate", query, "min", "id").Return(0, err).Once()
+
+	assert.Equal(t, err, iterators[0].Next(&user))
+	assert.Equal(t, err, iterators[1].Next(&user))
+
+	adapter.AssertExpectations(t)
+}
+
+func TestPartitionIterator_compositePrimary(t *testing.T) {
+	var (
+		entity = struct {
+			ID1, ID2 int `db:",primary"`
+		}{}
+		iterators = newPartitionIterators(context.TODO(), &testAdapter{}, From("composites"), 1, nil)
+	)
+
+	assert.Error(t, iterators[0].Next(&entity))
+}
+
+func TestPartitionIterator_canceled(t *testing.T) {
+	var (
+		user        User
+		ctx, cancel = context.WithCancel(context.TODO())
+		iterators   = newPartitionIterators(ctx, &testAdapter{}, From("users"), 1, nil)
+	)
+
+	cancel()
+	assert.Equal(t, context.Canceled, iterators[0].Next(&user))
+}
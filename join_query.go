@@ -22,7 +22,14 @@ func (jq JoinQuery) Build(query *Query) {
 
 func (jq *JoinQuery) Populate(query *Query, docMeta DocumentMeta) {
 	var (
-		assocMeta    = docMeta.Association(jq.Assoc)
+		assocMeta = docMeta.Association(jq.Assoc)
+	)
+
+	if assocMeta.isPolymorphicBelongsTo() {
+		panic("rel: join is not supported for polymorphic belongs to association (" + jq.Assoc + ")")
+	}
+
+	var (
 		assocDocMeta = assocMeta.DocumentMeta()
 	)
 
@@ -30,6 +37,11 @@ func (jq *JoinQuery) Populate(query *Query, docMeta DocumentMeta) {
 	jq.To = jq.Assoc + "." + assocMeta.ForeignField()
 	jq.From = docMeta.Table() + "." + assocMeta.ReferenceField()
 
+	// polymorphic target is shared by multiple tables, join only targets that belongs to this table.
+	if field := assocMeta.PolymorphicField(); field != "" {
+		jq.Filter = jq.Filter.AndEq(jq.Assoc+"."+field, assocMeta.PolymorphicValue())
+	}
+
 	// load association if defined and supported
 	if assocMeta.Type() == HasOne || assocMeta.Type() == BelongsTo {
 		var (
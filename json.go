@@ -0,0 +1,71 @@
+package rel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// jsonValue marshals value of field tagged with `db:"name,json"` when passed to database,
+// and unmarshals the column when scanned.
+type jsonValue struct {
+	value any
+}
+
+var (
+	_ sql.Scanner   = jsonValue{}
+	_ driver.Valuer = jsonValue{}
+)
+
+// Value returns json encoded value, nil pointer, map and slice are stored as NULL.
+func (jv jsonValue) Value() (driver.Value, error) {
+	if jv.value == nil {
+		return nil, nil
+	}
+
+	if rv := reflect.ValueOf(jv.value); (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(jv.value)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan decodes json column into destination, NULL resets destination to zero value.
+func (jv jsonValue) Scan(src any) error {
+	rv := reflect.ValueOf(jv.value)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic("rel: destination must be a pointer")
+	}
+
+	rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("rel: cannot scan %T into json field", src)
+	}
+
+	return json.Unmarshal(data, jv.value)
+}
+
+// wrapJSON wraps value as json value if field is tagged as json.
+func wrapJSON(meta DocumentMeta, field string, value any) any {
+	if _, ok := value.(jsonValue); ok || !meta.isJSON(field) {
+		return value
+	}
+
+	return jsonValue{value: value}
+}
@@ -0,0 +1,65 @@
+package rel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONValue_Value(t *testing.T) {
+	var (
+		nilMap   map[string]any
+		nilSlice []string
+		nilPtr   *struct{}
+	)
+
+	tests := []struct {
+		name     string
+		value    any
+		expected any
+	}{
+		{name: "nil", value: nil, expected: nil},
+		{name: "nil map", value: nilMap, expected: nil},
+		{name: "nil slice", value: nilSlice, expected: nil},
+		{name: "nil pointer", value: nilPtr, expected: nil},
+		{name: "map", value: map[string]any{"theme": "dark"}, expected: `{"theme":"dark"}`},
+		{name: "slice", value: []string{"go", "rel"}, expected: `["go","rel"]`},
+		{name: "struct", value: struct {
+			Theme string `json:"theme"`
+		}{Theme: "dark"}, expected: `{"theme":"dark"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := jsonValue{value: test.value}.Value()
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+
+	_, err := jsonValue{value: make(chan int)}.Value()
+	assert.NotNil(t, err)
+}
+
+func TestJSONValue_Scan(t *testing.T) {
+	var (
+		settings = map[string]any{"stale": true}
+		scanner  = jsonValue{value: &settings}
+	)
+
+	assert.Nil(t, scanner.Scan([]byte(`{"theme":"dark"}`)))
+	assert.Equal(t, map[string]any{"theme": "dark"}, settings)
+
+	assert.Nil(t, scanner.Scan(`{"theme":"light"}`))
+	assert.Equal(t, map[string]any{"theme": "light"}, settings)
+
+	assert.Nil(t, scanner.Scan(nil))
+	assert.Nil(t, settings)
+
+	assert.NotNil(t, scanner.Scan(1))
+	assert.NotNil(t, scanner.Scan("invalid"))
+
+	assert.Panics(t, func() {
+		_ = jsonValue{value: settings}.Scan(nil)
+	})
+}
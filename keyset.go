@@ -0,0 +1,200 @@
+package rel
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidPageCursor returned when page cursor can't be decoded or doesn't match the sort of the query.
+var ErrInvalidPageCursor = errors.New("rel: invalid page cursor")
+
+// DefaultPageSize used by FindPage when page size is not specified.
+const DefaultPageSize = 20
+
+// Keyset defines position of keyset pagination used by FindPage.
+type Keyset struct {
+	After  string
+	Before string
+}
+
+// Build query.
+func (k Keyset) Build(query *Query) {
+	query.KeysetQuery = k
+}
+
+// After fetches page of entities that comes after the cursor.
+func After(cursor string) Keyset {
+	return Keyset{After: cursor}
+}
+
+// Before fetches page of entities that comes before the cursor.
+func Before(cursor string) Keyset {
+	return Keyset{Before: cursor}
+}
+
+// PageSize specifies maximum number of entities returned by FindPage.
+func PageSize(size int) Limit {
+	return Limit(size)
+}
+
+// Page contains opaque cursors of adjacent pages returned by FindPage.
+// Cursor is empty when there's no adjacent page.
+type Page struct {
+	// Next cursor to be used with After.
+	Next string
+	// Prev cursor to be used with Before.
+	Prev string
+}
+
+type keysetCursor struct {
+	Fields []string          `json:"f"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// keysetSorts returns sorts with primary fields appended as tie breaker, so each entity has unique position.
+func keysetSorts(sorts []SortQuery, primaryFields []string) []SortQuery {
+	result := append([]SortQuery(nil), sorts...)
+
+	for _, field := range primaryFields {
+		found := false
+		for i := range sorts {
+			if keysetField(sorts[i].Field) == field {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			result = append(result, SortAsc(field))
+		}
+	}
+
+	return result
+}
+
+func reverseSorts(sorts []SortQuery) []SortQuery {
+	result := make([]SortQuery, len(sorts))
+	for i := range sorts {
+		result[i] = sorts[i]
+		result[i].Sort = -sorts[i].Sort
+		if result[i].Sort == 0 {
+			result[i].Sort = -1
+		}
+	}
+
+	return result
+}
+
+// keysetField returns struct field of sort field, table name is stripped from the field.
+func keysetField(field string) string {
+	if i := strings.LastIndexByte(field, '.'); i >= 0 {
+		return field[i+1:]
+	}
+
+	return field
+}
+
+// keysetValues returns values of sort fields in the document.
+func keysetValues(doc *Document, sorts []SortQuery) ([]any, error) {
+	values := make([]any, len(sorts))
+	for i := range sorts {
+		value, ok := doc.Value(keysetField(sorts[i].Field))
+		if !ok {
+			return nil, fmt.Errorf("rel: keyset pagination requires field %s to be present in entity", sorts[i].Field)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// keysetFilter builds filter that selects entities after (or before) given sort values.
+// It's an equivalent of (a, b) > (x, y) that supports mixed sort direction:
+// a > x OR (a = x AND b > y).
+func keysetFilter(sorts []SortQuery, values []any, before bool) FilterQuery {
+	filters := make([]FilterQuery, len(sorts))
+	for i := range sorts {
+		inner := make([]FilterQuery, 0, i+1)
+		for j := 0; j < i; j++ {
+			inner = append(inner, Eq(sorts[j].Field, values[j]))
+		}
+
+		if sorts[i].Asc() != before {
+			inner = append(inner, Gt(sorts[i].Field, values[i]))
+		} else {
+			inner = append(inner, Lt(sorts[i].Field, values[i]))
+		}
+
+		filters[i] = And(inner...)
+	}
+
+	return Or(filters...)
+}
+
+func encodePageCursor(doc *Document, sorts []SortQuery) (string, error) {
+	values, err := keysetValues(doc, sorts)
+	if err != nil {
+		return "", err
+	}
+
+	cursor := keysetCursor{
+		Fields: make([]string, len(sorts)),
+		Values: make([]json.RawMessage, len(sorts)),
+	}
+
+	for i := range sorts {
+		cursor.Fields[i] = sorts[i].Field
+		if cursor.Values[i], err = json.Marshal(values[i]); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodePageCursor decodes cursor into values of sort fields, values are decoded using type of the entity fields.
+func decodePageCursor(token string, sorts []SortQuery, meta DocumentMeta) ([]any, error) {
+	var (
+		cursor keysetCursor
+	)
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Fields) != len(sorts) || len(cursor.Values) != len(sorts) {
+		return nil, ErrInvalidPageCursor
+	}
+
+	values := make([]any, len(sorts))
+	for i := range sorts {
+		if cursor.Fields[i] != sorts[i].Field {
+			return nil, ErrInvalidPageCursor
+		}
+
+		typ, ok := meta.Type(keysetField(sorts[i].Field))
+		if !ok {
+			return nil, ErrInvalidPageCursor
+		}
+
+		rv := reflect.New(typ)
+		if err := json.Unmarshal(cursor.Values[i], rv.Interface()); err != nil {
+			return nil, ErrInvalidPageCursor
+		}
+
+		values[i] = rv.Elem().Interface()
+	}
+
+	return values, nil
+}
@@ -0,0 +1,115 @@
+package rel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysetSorts(t *testing.T) {
+	assert.Equal(t, []SortQuery{SortAsc("id")}, keysetSorts(nil, []string{"id"}))
+	assert.Equal(t, []SortQuery{SortDesc("users.id")}, keysetSorts([]SortQuery{SortDesc("users.id")}, []string{"id"}))
+	assert.Equal(t,
+		[]SortQuery{SortDesc("created_at"), SortAsc("follower_id"), SortAsc("following_id")},
+		keysetSorts([]SortQuery{SortDesc("created_at")}, []string{"follower_id", "following_id"}),
+	)
+}
+
+func TestReverseSorts(t *testing.T) {
+	assert.Equal(t,
+		[]SortQuery{SortDesc("name"), SortAsc("id")},
+		reverseSorts([]SortQuery{SortAsc("name"), SortDesc("id")}),
+	)
+
+	assert.Equal(t,
+		[]SortQuery{{Expr: Rank([]string{"title"}, "orm"), Sort: 1}, SortDesc("id")},
+		reverseSorts([]SortQuery{SortRank([]string{"title"}, "orm"), SortAsc("id")}),
+	)
+}
+
+func TestKeysetFilter(t *testing.T) {
+	var (
+		sorts  = []SortQuery{SortAsc("name"), SortDesc("age"), SortAsc("id")}
+		values = []any{"alice", 20, 1}
+	)
+
+	assert.Equal(t,
+		Gt("id", 1),
+		keysetFilter([]SortQuery{SortAsc("id")}, []any{1}, false),
+	)
+
+	assert.Equal(t,
+		Or(
+			Gt("name", "alice"),
+			And(Eq("name", "alice"), Lt("age", 20)),
+			And(Eq("name", "alice"), Eq("age", 20), Gt("id", 1)),
+		),
+		keysetFilter(sorts, values, false),
+	)
+
+	assert.Equal(t,
+		Or(
+			Lt("name", "alice"),
+			And(Eq("name", "alice"), Gt("age", 20)),
+			And(Eq("name", "alice"), Eq("age", 20), Lt("id", 1)),
+		),
+		keysetFilter(sorts, values, true),
+	)
+}
+
+func TestPageCursor(t *testing.T) {
+	var (
+		createdAt = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		user      = User{ID: 1, Name: "alice", CreatedAt: createdAt}
+		doc       = NewDocument(&user)
+		sorts     = []SortQuery{SortDesc("users.created_at"), SortAsc("name"), SortAsc("id")}
+	)
+
+	token, err := encodePageCursor(doc, sorts)
+	assert.Nil(t, err)
+
+	values, err := decodePageCursor(token, sorts, doc.Meta())
+	assert.Nil(t, err)
+	assert.Equal(t, []any{createdAt, "alice", 1}, values)
+}
+
+func TestPageCursor_missingField(t *testing.T) {
+	var (
+		doc   = NewDocument(&User{})
+		sorts = []SortQuery{SortAsc("count(id)")}
+	)
+
+	_, err := encodePageCursor(doc, sorts)
+	assert.EqualError(t, err, "rel: keyset pagination requires field count(id) to be present in entity")
+}
+
+func TestPageCursor_invalid(t *testing.T) {
+	var (
+		doc   = NewDocument(&User{ID: 1})
+		sorts = []SortQuery{SortAsc("id")}
+	)
+
+	token, err := encodePageCursor(doc, sorts)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		sorts []SortQuery
+	}{
+		{name: "not base64", token: "!", sorts: sorts},
+		{name: "not json", token: "bm90IGpzb24", sorts: sorts},
+		{name: "different sort", token: token, sorts: []SortQuery{SortAsc("name")}},
+		{name: "different length", token: token, sorts: []SortQuery{SortAsc("name"), SortAsc("id")}},
+		{name: "unknown field", token: "eyJmIjpbInVua25vd24iXSwidiI6WzFdfQ", sorts: []SortQuery{SortAsc("unknown")}},
+		{name: "invalid value", token: "eyJmIjpbImlkIl0sInYiOlsiYSJdfQ", sorts: sorts},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodePageCursor(test.token, test.sorts, doc.Meta())
+			assert.Equal(t, ErrInvalidPageCursor, err)
+		})
+	}
+}
@@ -0,0 +1,419 @@
+// Package memory implements rel.Adapter that stores tables in memory.
+//
+// It's intended for tests and prototyping, where running a database isn't desirable.
+// Tables are created on the first insert, or can be defined using migration.
+// Transaction works on a copy-on-write snapshot of tables, and replaces the modified tables on commit.
+// Failed write aborts the transaction until it's rolled back to a savepoint, commit of aborted transaction is rolled back.
+// Commit fails with rel.ErrSerialization when a table written by the transaction is modified after the transaction begins,
+// so the transaction can be retried.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-rel/rel"
+)
+
+var (
+	// ErrNotSupported returned when query or operation can't be evaluated by memory adapter.
+	ErrNotSupported = errors.New("memory: operation is not supported")
+
+	// ErrNoTransaction returned when transaction operation is called outside of transaction,
+	// or after the transaction is committed or rolled back.
+	ErrNoTransaction = errors.New("memory: no active transaction")
+
+	// ErrReadOnlyTransaction returned when writing inside read only transaction.
+	ErrReadOnlyTransaction = errors.New("memory: cannot write in read only transaction")
+
+	// ErrSavepointNotFound returned when rolling back to or releasing unknown savepoint.
+	ErrSavepointNotFound = errors.New("memory: savepoint not found")
+
+	// ErrTransactionAborted returned when using or committing transaction after a write inside it failed,
+	// roll back to a savepoint or roll back the transaction to continue.
+	ErrTransactionAborted = errors.New("memory: transaction is aborted")
+)
+
+// Adapter definition for in memory database.
+type Adapter struct {
+	store        *store
+	tx           *transaction
+	instrumenter rel.Instrumenter
+}
+
+var _ rel.Adapter = (*Adapter)(nil)
+
+type transaction struct {
+	lock       sync.Mutex
+	snapshot   *snapshot
+	savepoints []savepoint
+	readOnly   bool
+	aborted    bool
+	done       bool
+}
+
+// New in memory adapter with empty tables.
+func New() *Adapter {
+	return &Adapter{
+		store: &store{tables: make(map[string]*table)},
+	}
+}
+
+// Name of database adapter.
+func (a *Adapter) Name() string {
+	return "memory"
+}
+
+// SupportPartitionLimit returns true, partition limit is evaluated after sorting the result.
+func (a *Adapter) SupportPartitionLimit() bool {
+	return true
+}
+
+// Close database connection.
+func (a *Adapter) Close() error {
+	return nil
+}
+
+// Instrumentation set instrumenter for this adapter.
+func (a *Adapter) Instrumentation(instrumenter rel.Instrumenter) {
+	a.instrumenter = instrumenter
+}
+
+// Ping database.
+func (a *Adapter) Ping(ctx context.Context) error {
+	return nil
+}
+
+// read runs fn against committed tables, or against snapshot of current transaction.
+func (a *Adapter) read(fn func(e executor) error) error {
+	if a.tx != nil {
+		a.tx.lock.Lock()
+		defer a.tx.lock.Unlock()
+
+		if a.tx.done {
+			return ErrNoTransaction
+		}
+
+		if a.tx.aborted {
+			return ErrTransactionAborted
+		}
+
+		return fn(executor{snapshot: a.tx.snapshot})
+	}
+
+	a.store.lock.Lock()
+	defer a.store.lock.Unlock()
+
+	return fn(executor{snapshot: a.store.snapshot()})
+}
+
+// write runs fn against a snapshot, changes are discarded when fn returns an error.
+// Outside of transaction, changes are committed immediately.
+// Inside transaction, fn runs against the snapshot of the transaction and the transaction is aborted when fn returns an error,
+// so the snapshot is only taken once per transaction or savepoint instead of once per write.
+func (a *Adapter) write(fn func(e executor) error) error {
+	if a.tx != nil {
+		a.tx.lock.Lock()
+		defer a.tx.lock.Unlock()
+
+		if a.tx.done {
+			return ErrNoTransaction
+		}
+
+		if a.tx.readOnly {
+			return ErrReadOnlyTransaction
+		}
+
+		if a.tx.aborted {
+			return ErrTransactionAborted
+		}
+
+		if err := fn(executor{snapshot: a.tx.snapshot}); err != nil {
+			a.tx.aborted = true
+			return err
+		}
+
+		return nil
+	}
+
+	a.store.lock.Lock()
+	defer a.store.lock.Unlock()
+
+	s := a.store.snapshot()
+	if err := fn(executor{snapshot: s}); err != nil {
+		return err
+	}
+
+	return a.store.commit(s)
+}
+
+// Aggregate record using given query.
+func (a *Adapter) Aggregate(ctx context.Context, query rel.Query, mode string, field string) (int, error) {
+	var (
+		result int
+		finish = a.instrumenter.Observe(ctx, "adapter-aggregate", mode+"("+field+") from "+query.Table)
+		err    = a.read(func(e executor) error {
+			records, err := e.aggregateRecords(query)
+			if err != nil {
+				return err
+			}
+
+			switch v := aggregate(mode, field, false, records).(type) {
+			case int64:
+				result = int(v)
+			case float64:
+				result = int(v)
+			}
+
+			return nil
+		})
+	)
+
+	finish(err)
+	return result, err
+}
+
+// Query performs query operation.
+func (a *Adapter) Query(ctx context.Context, query rel.Query) (rel.Cursor, error) {
+	var (
+		cur    *cursor
+		finish = a.instrumenter.Observe(ctx, "adapter-query", "select from "+query.Table)
+		err    = a.read(func(e executor) error {
+			var err error
+			cur, err = e.query(query)
+			return err
+		})
+	)
+
+	finish(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return cur, nil
+}
+
+// Insert inserts a record to database and returns its id.
+func (a *Adapter) Insert(ctx context.Context, query rel.Query, primaryField string, mutates map[string]rel.Mutate, onConflict rel.OnConflict) (any, error) {
+	ids, err := a.insert(ctx, query, primaryField, []map[string]rel.Mutate{mutates}, onConflict)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids[0], nil
+}
+
+// InsertAll inserts multiple records to database and returns its ids.
+func (a *Adapter) InsertAll(ctx context.Context, query rel.Query, primaryField string, fields []string, bulkMutates []map[string]rel.Mutate, onConflict rel.OnConflict) ([]any, error) {
+	return a.insert(ctx, query, primaryField, bulkMutates, onConflict)
+}
+
+func (a *Adapter) insert(ctx context.Context, query rel.Query, primaryField string, bulkMutates []map[string]rel.Mutate, onConflict rel.OnConflict) ([]any, error) {
+	var (
+		ids    []any
+		finish = a.instrumenter.Observe(ctx, "adapter-insert", "insert into "+query.Table)
+		err    = a.write(func(e executor) error {
+			var err error
+			ids, err = e.insert(query, primaryField, bulkMutates, onConflict)
+			return err
+		})
+	)
+
+	finish(err)
+	return ids, err
+}
+
+// Update updates a record in database.
+func (a *Adapter) Update(ctx context.Context, query rel.Query, primaryField string, mutates map[string]rel.Mutate) (int, error) {
+	var (
+		updated int
+		finish  = a.instrumenter.Observe(ctx, "adapter-update", "update "+query.Table)
+		err     = a.write(func(e executor) error {
+			var err error
+			updated, err = e.update(query, mutates)
+			return err
+		})
+	)
+
+	finish(err)
+	return updated, err
+}
+
+// Delete deletes all results that match the query.
+func (a *Adapter) Delete(ctx context.Context, query rel.Query) (int, error) {
+	var (
+		deleted int
+		finish  = a.instrumenter.Observe(ctx, "adapter-delete", "delete from "+query.Table)
+		err     = a.write(func(e executor) error {
+			var err error
+			deleted, err = e.delete(query)
+			return err
+		})
+	)
+
+	finish(err)
+	return deleted, err
+}
+
+// Exec is not supported, since there's no sql to be executed.
+func (a *Adapter) Exec(ctx context.Context, stmt string, args []any) (int64, int64, error) {
+	return 0, 0, fmt.Errorf("%w: exec %s", ErrNotSupported, stmt)
+}
+
+// Begin begins a new transaction on a snapshot of committed tables.
+func (a *Adapter) Begin(ctx context.Context, options rel.TransactionOptions) (rel.Adapter, error) {
+	if a.tx != nil {
+		return nil, fmt.Errorf("%w: nested transaction, use savepoint instead", ErrNotSupported)
+	}
+
+	finish := a.instrumenter.Observe(ctx, "adapter-begin", "begin")
+
+	a.store.lock.Lock()
+	s := a.store.snapshot()
+	a.store.lock.Unlock()
+
+	finish(nil)
+
+	return &Adapter{
+		store:        a.store,
+		instrumenter: a.instrumenter,
+		tx: &transaction{
+			snapshot: s,
+			readOnly: options.ReadOnly,
+		},
+	}, nil
+}
+
+// Commit commits current transaction.
+func (a *Adapter) Commit(ctx context.Context) error {
+	finish := a.instrumenter.Observe(ctx, "adapter-commit", "commit")
+	err := a.finish(true)
+	finish(err)
+
+	return err
+}
+
+// Rollback revert current transaction.
+func (a *Adapter) Rollback(ctx context.Context) error {
+	finish := a.instrumenter.Observe(ctx, "adapter-rollback", "rollback")
+	err := a.finish(false)
+	finish(err)
+
+	return err
+}
+
+func (a *Adapter) finish(commit bool) error {
+	if a.tx == nil {
+		return ErrNoTransaction
+	}
+
+	a.tx.lock.Lock()
+	defer a.tx.lock.Unlock()
+
+	if a.tx.done {
+		return ErrNoTransaction
+	}
+
+	var err error
+	if commit && a.tx.aborted {
+		err = ErrTransactionAborted
+	} else if commit {
+		a.store.lock.Lock()
+		err = a.store.commit(a.tx.snapshot)
+		a.store.lock.Unlock()
+	}
+
+	a.tx.done = true
+	a.tx.savepoints = nil
+	return err
+}
+
+// Savepoint creates a named savepoint inside the current transaction.
+func (a *Adapter) Savepoint(ctx context.Context, name string) error {
+	return a.savepoint(ctx, "adapter-savepoint", "savepoint "+name, func(tx *transaction) error {
+		if tx.aborted {
+			return ErrTransactionAborted
+		}
+
+		tx.savepoints = append(tx.savepoints, savepoint{name: name, state: tx.snapshot.save()})
+		return nil
+	})
+}
+
+// RollbackTo rolls back the current transaction to the named savepoint.
+func (a *Adapter) RollbackTo(ctx context.Context, name string) error {
+	return a.savepoint(ctx, "adapter-rollback-to", "rollback to savepoint "+name, func(tx *transaction) error {
+		i := tx.lookup(name)
+		if i < 0 {
+			return ErrSavepointNotFound
+		}
+
+		tx.snapshot.restore(tx.savepoints[i].state)
+		tx.savepoints = tx.savepoints[:i+1]
+		tx.aborted = false
+		return nil
+	})
+}
+
+// Release destroys the named savepoint, keeping changes made after it was created.
+func (a *Adapter) Release(ctx context.Context, name string) error {
+	return a.savepoint(ctx, "adapter-release", "release savepoint "+name, func(tx *transaction) error {
+		i := tx.lookup(name)
+		if i < 0 {
+			return ErrSavepointNotFound
+		}
+
+		if tx.aborted {
+			return ErrTransactionAborted
+		}
+
+		tx.savepoints = tx.savepoints[:i]
+		return nil
+	})
+}
+
+func (a *Adapter) savepoint(ctx context.Context, op string, message string, fn func(tx *transaction) error) error {
+	finish := a.instrumenter.Observe(ctx, op, message)
+
+	var err error
+	if a.tx == nil {
+		err = ErrNoTransaction
+	} else {
+		a.tx.lock.Lock()
+		if a.tx.done {
+			err = ErrNoTransaction
+		} else {
+			err = fn(a.tx)
+		}
+		a.tx.lock.Unlock()
+	}
+
+	finish(err)
+	return err
+}
+
+// lookup returns index of the latest savepoint with given name.
+func (tx *transaction) lookup(name string) int {
+	for i := len(tx.savepoints) - 1; i >= 0; i-- {
+		if tx.savepoints[i].name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Apply table and index migration.
+func (a *Adapter) Apply(ctx context.Context, migration rel.Migration) error {
+	var (
+		finish = a.instrumenter.Observe(ctx, "adapter-apply", fmt.Sprintf("apply %T migration", migration))
+		err    = a.write(func(e executor) error {
+			return migrate(e.snapshot, migration)
+		})
+	)
+
+	finish(err)
+	return err
+}
@@ -0,0 +1,1598 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/join"
+	"github.com/go-rel/rel/sort"
+	"github.com/go-rel/rel/where"
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	ID     int
+	UserID int
+	City   string
+}
+
+type User struct {
+	ID        int
+	Name      string
+	Age       int
+	Addresses []Address `ref:"id" fk:"user_id" autosave:"true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Book struct {
+	ID    int
+	Isbn  string
+	Title string
+	Stock int
+}
+
+func seed(t *testing.T, repo rel.Repository) []User {
+	users := []User{
+		{Name: "alice", Age: 20, Addresses: []Address{{City: "Jakarta"}, {City: "Bandung"}}},
+		{Name: "bob", Age: 30, Addresses: []Address{{City: "Surabaya"}}},
+		{Name: "carol", Age: 30},
+	}
+
+	for i := range users {
+		assert.Nil(t, repo.Insert(context.TODO(), &users[i]))
+	}
+
+	return users
+}
+
+func setup(t *testing.T, repo rel.Repository) {
+	var (
+		schema rel.Schema
+	)
+
+	schema.CreateTable("books", func(t *rel.Table) {
+		t.ID("id")
+		t.String("isbn", rel.Unique(true))
+		t.String("title")
+		t.Int("stock", rel.Default(10))
+	})
+
+	for _, migration := range schema.Migrations {
+		assert.Nil(t, repo.Adapter(context.TODO()).Apply(context.TODO(), migration))
+	}
+}
+
+func TestAdapter_Name(t *testing.T) {
+	var (
+		adapter = New()
+	)
+
+	assert.Equal(t, "memory", adapter.Name())
+	assert.Nil(t, adapter.Ping(context.TODO()))
+	assert.Nil(t, adapter.Close())
+}
+
+func TestAdapter_Insert(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		users = seed(t, repo)
+		user  User
+	)
+
+	assert.Equal(t, 1, users[0].ID)
+	assert.Equal(t, 3, users[2].ID)
+	assert.Equal(t, 2, users[0].Addresses[1].ID)
+
+	assert.Nil(t, repo.Find(context.TODO(), &user, where.Eq("name", "alice"), rel.Preload("addresses")))
+	assert.Equal(t, users[0].ID, user.ID)
+	assert.Equal(t, 20, user.Age)
+	assert.False(t, user.CreatedAt.IsZero())
+	assert.Len(t, user.Addresses, 2)
+	assert.Equal(t, "Jakarta", user.Addresses[0].City)
+}
+
+func TestAdapter_InsertAll(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		books = []Book{{Isbn: "1", Title: "Go"}, {ID: 10, Isbn: "2", Title: "Rel"}, {Isbn: "3", Title: "Memory"}}
+	)
+
+	assert.Nil(t, repo.InsertAll(context.TODO(), &books))
+	assert.Equal(t, 1, books[0].ID)
+	assert.Equal(t, 10, books[1].ID)
+	assert.Equal(t, 11, books[2].ID)
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "books"))
+}
+
+func TestAdapter_Insert_constraint(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		book1 = Book{Isbn: "1", Title: "Go"}
+		book2 = Book{Isbn: "1", Title: "Rel"}
+		book3 = Book{ID: 1, Isbn: "2", Title: "Rel"}
+	)
+
+	setup(t, repo)
+
+	assert.Nil(t, repo.Insert(context.TODO(), &book1))
+
+	err := repo.Insert(context.TODO(), &book2)
+	assert.True(t, errors.Is(err, rel.ErrUniqueConstraint))
+	assert.Equal(t, "books_isbn_key", err.(rel.ConstraintError).Key)
+
+	assert.True(t, errors.Is(repo.Insert(context.TODO(), &book3), rel.ErrPrimaryKeyConstraint))
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "books"))
+}
+
+func TestAdapter_Insert_onConflict(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		book1 = Book{Isbn: "1", Title: "Go"}
+		book2 = Book{Isbn: "1", Title: "Rel"}
+		book3 = Book{Isbn: "1", Title: "Memory"}
+		book  Book
+	)
+
+	setup(t, repo)
+
+	assert.Nil(t, repo.Insert(context.TODO(), &book1))
+	assert.Nil(t, repo.Insert(context.TODO(), &book2, rel.OnConflictKeyIgnore("isbn")))
+	assert.Equal(t, book1.ID, book2.ID)
+	assert.Nil(t, repo.Find(context.TODO(), &book, where.Eq("isbn", "1")))
+	assert.Equal(t, "Go", book.Title)
+
+	assert.Nil(t, repo.Insert(context.TODO(), &book3, rel.OnConflictKeyReplace("isbn")))
+	assert.Equal(t, book1.ID, book3.ID)
+	assert.Nil(t, repo.Find(context.TODO(), &book, where.Eq("isbn", "1")))
+	assert.Equal(t, "Memory", book.Title)
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "books"))
+
+	assert.True(t, errors.Is(repo.Insert(context.TODO(), &Book{Isbn: "1"}, rel.OnConflictKeyIgnore("title")), rel.ErrUniqueConstraint))
+	assert.True(t, errors.Is(repo.Insert(context.TODO(), &Book{Isbn: "2"}, rel.OnConflictFragment("DO NOTHING")), ErrNotSupported))
+}
+
+func TestAdapter_Update(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		users = seed(t, repo)
+		user  User
+	)
+
+	users[0].Name = "alice updated"
+	assert.Nil(t, repo.Update(context.TODO(), &users[0]))
+	assert.Nil(t, repo.Find(context.TODO(), &user, where.Eq("id", users[0].ID)))
+	assert.Equal(t, "alice updated", user.Name)
+
+	assert.Nil(t, repo.Update(context.TODO(), &users[1], rel.Inc("age")))
+	assert.Equal(t, 31, users[1].Age)
+
+	updated, err := repo.UpdateAny(context.TODO(), rel.From("users").Where(where.Eq("age", 30)), rel.DecBy("age", 5))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Nil(t, repo.Find(context.TODO(), &user, where.Eq("id", users[2].ID)))
+	assert.Equal(t, 25, user.Age)
+
+	_, err = repo.UpdateAny(context.TODO(), rel.From("users"), rel.SetFragment("age = age * 2"))
+	assert.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestAdapter_Update_constraint(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		book1 = Book{Isbn: "1", Title: "Go"}
+		book2 = Book{Isbn: "2", Title: "Rel"}
+		book  Book
+	)
+
+	setup(t, repo)
+	repo.MustInsert(context.TODO(), &book1)
+	repo.MustInsert(context.TODO(), &book2)
+
+	book2.Isbn = "1"
+	book2.Title = "Changed"
+	assert.True(t, errors.Is(repo.Update(context.TODO(), &book2), rel.ErrUniqueConstraint))
+	assert.Nil(t, repo.Find(context.TODO(), &book, where.Eq("id", book2.ID)))
+	assert.Equal(t, "2", book.Isbn)
+	assert.Equal(t, "Rel", book.Title)
+}
+
+func TestAdapter_Delete(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		users = seed(t, repo)
+	)
+
+	assert.Nil(t, repo.Delete(context.TODO(), &users[0], rel.Cascade(true)))
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "users"))
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "addresses"))
+
+	deleted, err := repo.DeleteAny(context.TODO(), rel.From("users").Where(where.Gt("age", 100)))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, deleted)
+
+	deleted, err = repo.DeleteAny(context.TODO(), rel.From("users"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "users"))
+}
+
+func TestAdapter_Query(t *testing.T) {
+	var (
+		repo = rel.New(New())
+		_    = seed(t, repo)
+	)
+
+	tests := []struct {
+		name    string
+		queries []rel.Querier
+		names   []string
+	}{
+		{
+			name:  "all",
+			names: []string{"alice", "bob", "carol"},
+		},
+		{
+			name:    "eq",
+			queries: []rel.Querier{where.Eq("age", 30)},
+			names:   []string{"bob", "carol"},
+		},
+		{
+			name:    "ne",
+			queries: []rel.Querier{where.Ne("name", "bob")},
+			names:   []string{"alice", "carol"},
+		},
+		{
+			name:    "lt and gte",
+			queries: []rel.Querier{where.Lt("age", 30).AndGte("id", 1)},
+			names:   []string{"alice"},
+		},
+		{
+			name:    "or",
+			queries: []rel.Querier{where.Eq("name", "alice").OrEq("name", "carol")},
+			names:   []string{"alice", "carol"},
+		},
+		{
+			name:    "not",
+			queries: []rel.Querier{where.Not(where.Eq("name", "alice"))},
+			names:   []string{"bob", "carol"},
+		},
+		{
+			name:    "in",
+			queries: []rel.Querier{where.InString("name", []string{"bob", "dave"})},
+			names:   []string{"bob"},
+		},
+		{
+			name:    "nin",
+			queries: []rel.Querier{where.NinInt("id", []int{1, 2})},
+			names:   []string{"carol"},
+		},
+		{
+			name:    "like",
+			queries: []rel.Querier{where.Like("name", "%o%")},
+			names:   []string{"bob", "carol"},
+		},
+		{
+			name:    "not like",
+			queries: []rel.Querier{where.NotLike("name", "_o%")},
+			names:   []string{"alice", "carol"},
+		},
+		{
+			name:    "between",
+			queries: []rel.Querier{where.Between("age", 25, 30)},
+			names:   []string{"bob", "carol"},
+		},
+		{
+			name:    "not between",
+			queries: []rel.Querier{where.NotBetween("age", 25, 30)},
+			names:   []string{"alice"},
+		},
+		{
+			name:    "ilike",
+			queries: []rel.Querier{where.ILike("name", "%O%")},
+			names:   []string{"bob", "carol"},
+		},
+		{
+			name:    "not ilike",
+			queries: []rel.Querier{where.NotILike("name", "B%")},
+			names:   []string{"alice", "carol"},
+		},
+		{
+			name:    "regexp",
+			queries: []rel.Querier{where.Regexp("name", "^(a|c)")},
+			names:   []string{"alice", "carol"},
+		},
+		{
+			name:    "not regexp",
+			queries: []rel.Querier{where.NotRegexp("name", "o")},
+			names:   []string{"alice"},
+		},
+		{
+			name:    "not of between",
+			queries: []rel.Querier{where.Not(where.Between("age", 25, 30))},
+			names:   []string{"alice"},
+		},
+		{
+			name:    "nil",
+			queries: []rel.Querier{where.Nil("name")},
+		},
+		{
+			name:    "not nil",
+			queries: []rel.Querier{where.NotNil("name"), rel.Limit(1)},
+			names:   []string{"alice"},
+		},
+		{
+			name:    "sort, offset and limit",
+			queries: []rel.Querier{sort.Desc("age"), sort.Asc("name"), rel.Offset(1), rel.Limit(1)},
+			names:   []string{"carol"},
+		},
+		{
+			name:    "sub query",
+			queries: []rel.Querier{where.In("id", rel.Select("user_id").From("addresses").Where(where.Eq("city", "Surabaya")))},
+			names:   []string{"bob"},
+		},
+		{
+			name:    "sub query all",
+			queries: []rel.Querier{where.Gte("age", rel.All(rel.Select("age").From("users")))},
+			names:   []string{"bob", "carol"},
+		},
+		{
+			name:    "join",
+			queries: []rel.Querier{rel.Select("users.*").Distinct(), join.On("addresses", "addresses.user_id", "users.id"), sort.Asc("users.id")},
+			names:   []string{"alice", "bob"},
+		},
+		{
+			name:    "left join",
+			queries: []rel.Querier{rel.Select("users.*"), rel.NewJoinWith("LEFT JOIN", "addresses", "addresses.user_id", "users.id"), where.Nil("addresses.id")},
+			names:   []string{"carol"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				users []User
+				names []string
+			)
+
+			assert.Nil(t, repo.FindAll(context.TODO(), &users, test.queries...))
+			for i := range users {
+				names = append(names, users[i].Name)
+			}
+
+			assert.Equal(t, test.names, names)
+		})
+	}
+}
+
+func TestAdapter_Query_containment(t *testing.T) {
+	type Post struct {
+		ID   int
+		Tags []string
+	}
+
+	var (
+		repo  = rel.New(New())
+		posts = []Post{
+			{Tags: []string{"go", "orm"}},
+			{Tags: []string{"go"}},
+			{Tags: []string{"rust"}},
+		}
+	)
+
+	for i := range posts {
+		assert.Nil(t, repo.Insert(context.TODO(), &posts[i]))
+	}
+
+	tests := []struct {
+		name   string
+		filter rel.FilterQuery
+		count  int
+	}{
+		{
+			name:   "contains",
+			filter: where.Contains("tags", []string{"go"}),
+			count:  2,
+		},
+		{
+			name:   "contains all",
+			filter: where.Contains("tags", []string{"go", "orm"}),
+			count:  1,
+		},
+		{
+			name:   "contained by",
+			filter: where.ContainedBy("tags", []string{"go", "rust"}),
+			count:  2,
+		},
+		{
+			name:   "overlaps",
+			filter: where.Overlaps("tags", []string{"orm", "rust"}),
+			count:  2,
+		},
+		{
+			name:   "not overlaps",
+			filter: where.Not(where.Overlaps("tags", []string{"orm", "rust"})),
+			count:  1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			count, err := repo.Count(context.TODO(), "posts", test.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, test.count, count)
+		})
+	}
+
+	_, err := repo.Count(context.TODO(), "posts", where.Contains("tags", `["go"]`))
+	assert.True(t, errors.Is(err, rel.ErrUnsupportedFilter))
+	assert.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestAdapter_JSON(t *testing.T) {
+	type Profile struct {
+		ID       int
+		Name     string
+		Settings map[string]any `db:"settings,json"`
+		Tags     []string       `db:"tags,json"`
+	}
+
+	var (
+		repo     = rel.New(New())
+		profiles = []Profile{
+			{Name: "alice", Settings: map[string]any{"theme": "dark", "notification": map[string]any{"email": true}}, Tags: []string{"admin"}},
+			{Name: "bob", Settings: map[string]any{"theme": "light", "font_size": 12}},
+			{Name: "carol"},
+		}
+	)
+
+	for i := range profiles {
+		assert.Nil(t, repo.Insert(context.TODO(), &profiles[i]))
+	}
+
+	tests := []struct {
+		name   string
+		filter rel.FilterQuery
+		names  []string
+	}{
+		{
+			name:   "eq",
+			filter: where.JSONEq("settings", "$.theme", "dark"),
+			names:  []string{"alice"},
+		},
+		{
+			name:   "eq number",
+			filter: where.JSONEq("settings", "$.font_size", 12),
+			names:  []string{"bob"},
+		},
+		{
+			name:   "eq nested",
+			filter: where.JSONEq("settings", "$.notification.email", true),
+			names:  []string{"alice"},
+		},
+		{
+			name:   "eq array index",
+			filter: where.JSONEq("tags", "$[0]", "admin"),
+			names:  []string{"alice"},
+		},
+		{
+			name:   "ne",
+			filter: where.JSONNe("settings", "$.theme", "dark"),
+			names:  []string{"bob"},
+		},
+		{
+			name:   "has key",
+			filter: where.JSONHasKey("settings", "$.notification"),
+			names:  []string{"alice"},
+		},
+		{
+			name:   "not has key",
+			filter: where.JSONNotHasKey("settings", "$.notification"),
+			names:  []string{"bob"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				result []Profile
+				names  []string
+			)
+
+			assert.Nil(t, repo.FindAll(context.TODO(), &result, test.filter))
+			for i := range result {
+				names = append(names, result[i].Name)
+			}
+
+			assert.Equal(t, test.names, names)
+		})
+	}
+
+	var profile Profile
+	assert.Nil(t, repo.Find(context.TODO(), &profile, where.Eq("name", "alice")))
+	assert.Equal(t, profiles[0].Settings["theme"], profile.Settings["theme"])
+	assert.Equal(t, []string{"admin"}, profile.Tags)
+
+	assert.Nil(t, repo.Update(context.TODO(), &profile, rel.JSONSet("settings", "$.notification.sms", false)))
+	assert.Equal(t, map[string]any{"theme": "dark", "notification": map[string]any{"email": true, "sms": false}}, profile.Settings)
+
+	assert.Nil(t, repo.Update(context.TODO(), &profile, rel.JSONRemove("settings", "$.notification")))
+	assert.Equal(t, map[string]any{"theme": "dark"}, profile.Settings)
+
+	updated, err := repo.UpdateAny(context.TODO(), rel.From("profiles").Where(where.Eq("name", "carol")), rel.JSONSet("settings", "$.theme", "dark"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "profiles", where.JSONEq("settings", "$.theme", "dark")))
+
+	_, err = repo.UpdateAny(context.TODO(), rel.From("profiles"), rel.JSONSet("settings", "theme[", "dark"))
+	assert.NotNil(t, err)
+}
+
+func TestAdapter_Query_group(t *testing.T) {
+	var (
+		repo   = rel.New(New())
+		_      = seed(t, repo)
+		result []struct {
+			Age   int
+			Total int
+		}
+	)
+
+	cur, err := repo.Adapter(context.TODO()).Query(context.TODO(), rel.Select("age", "count(id) as total").From("users").Group("age").Having(where.Gt("count(id)", 1)))
+	assert.Nil(t, err)
+
+	col := rel.NewCollection(&result)
+	for cur.Next() {
+		doc := col.Add()
+		fields, _ := cur.Fields()
+		assert.Nil(t, cur.Scan(doc.Scanners(fields)...))
+	}
+
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, 30, result[0].Age)
+	assert.Equal(t, 2, result[0].Total)
+}
+
+func TestAdapter_Query_unsupported(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		users []User
+	)
+
+	assert.True(t, errors.Is(repo.FindAll(context.TODO(), &users, where.Fragment("age > ?", 10)), ErrNotSupported))
+	assert.True(t, errors.Is(repo.FindAll(context.TODO(), &users, where.Fragment("age > ?", 10)), rel.ErrUnsupportedFilter))
+	assert.True(t, errors.Is(repo.FindAll(context.TODO(), &users, rel.SQL("SELECT * FROM users")), ErrNotSupported))
+	assert.True(t, errors.Is(repo.FindAll(context.TODO(), &users, rel.NewJoinWith("RIGHT JOIN", "addresses", "addresses.user_id", "users.id")), ErrNotSupported))
+
+	_, _, err := repo.Adapter(context.TODO()).Exec(context.TODO(), "DELETE FROM users", nil)
+	assert.True(t, errors.Is(err, ErrNotSupported))
+}
+
+func TestAdapter_Aggregate(t *testing.T) {
+	var (
+		repo = rel.New(New())
+		_    = seed(t, repo)
+	)
+
+	tests := []struct {
+		mode   string
+		field  string
+		result int
+	}{
+		{mode: "count", field: "*", result: 3},
+		{mode: "count", field: "name", result: 3},
+		{mode: "sum", field: "age", result: 80},
+		{mode: "avg", field: "age", result: 26},
+		{mode: "max", field: "age", result: 30},
+		{mode: "min", field: "age", result: 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			result, err := repo.Aggregate(context.TODO(), rel.From("users"), test.mode, test.field)
+			assert.Nil(t, err)
+			assert.Equal(t, test.result, result)
+		})
+	}
+
+	result, err := repo.Aggregate(context.TODO(), rel.From("unknown"), "sum", "age")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result)
+}
+
+func TestAdapter_Transaction(t *testing.T) {
+	var (
+		repo = rel.New(New())
+		user = User{Name: "alice"}
+	)
+
+	assert.Nil(t, repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		repo.MustInsert(ctx, &user)
+		assert.Equal(t, 1, repo.MustCount(ctx, "users"))
+		assert.Equal(t, 0, repo.MustCount(context.TODO(), "users"))
+		return nil
+	}))
+
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "users"))
+}
+
+func TestAdapter_Transaction_rollback(t *testing.T) {
+	var (
+		repo = rel.New(New())
+		_    = seed(t, repo)
+	)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		repo.MustDeleteAny(ctx, rel.From("users"))
+		repo.MustInsert(ctx, &User{Name: "dave"})
+		return errors.New("rollback")
+	})
+
+	assert.Equal(t, errors.New("rollback"), err)
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "users"))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "users", where.Eq("name", "dave")))
+}
+
+func TestAdapter_Transaction_conflict(t *testing.T) {
+	var (
+		adapter = New()
+		repo    = rel.New(adapter)
+		_       = seed(t, repo)
+	)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		repo.MustInsert(ctx, &User{Name: "in-tx"})
+		repo.MustInsert(context.TODO(), &User{Name: "outside"})
+		return nil
+	})
+
+	assert.ErrorIs(t, err, rel.ErrSerialization)
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "users", where.Eq("name", "outside")))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "users", where.Eq("name", "in-tx")))
+
+	attempts := 0
+	assert.Nil(t, repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		repo.MustInsert(ctx, &User{Name: "retried"})
+		if attempts == 1 {
+			repo.MustInsert(context.TODO(), &User{Name: "concurrent"})
+		}
+
+		return nil
+	}, rel.Retry(3, 0)))
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, []string{"alice", "bob", "carol", "outside", "concurrent", "retried"}, names(t, repo))
+}
+
+func names(t *testing.T, repo rel.Repository) []string {
+	var users []User
+	assert.Nil(t, repo.FindAll(context.TODO(), &users, rel.NewSortAsc("id")))
+
+	result := make([]string, len(users))
+	for i := range users {
+		result[i] = users[i].Name
+	}
+
+	return result
+}
+
+func TestAdapter_Transaction_nested(t *testing.T) {
+	var (
+		repo = rel.New(New())
+	)
+
+	assert.Nil(t, repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		repo.MustInsert(ctx, &User{Name: "alice"})
+
+		assert.NotNil(t, repo.Transaction(ctx, func(ctx context.Context) error {
+			repo.MustInsert(ctx, &User{Name: "bob"})
+			return errors.New("rollback")
+		}))
+
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			repo.MustInsert(ctx, &User{Name: "carol"})
+			return nil
+		})
+	}))
+
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "users"))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "users", where.Eq("name", "bob")))
+}
+
+func TestAdapter_Transaction_aborted(t *testing.T) {
+	var (
+		repo = rel.New(New())
+	)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		repo.MustInsert(ctx, &User{ID: 1, Name: "alice"})
+
+		assert.NotNil(t, repo.Transaction(ctx, func(ctx context.Context) error {
+			return repo.Insert(ctx, &User{ID: 1, Name: "bob"})
+		}))
+		repo.MustInsert(ctx, &User{ID: 2, Name: "carol"})
+
+		assert.NotNil(t, repo.Insert(ctx, &User{ID: 2, Name: "dave"}))
+		assert.Equal(t, ErrTransactionAborted, repo.Insert(ctx, &User{ID: 3, Name: "erin"}))
+		_, err := repo.Count(ctx, "users")
+		assert.Equal(t, ErrTransactionAborted, err)
+
+		return nil
+	})
+
+	assert.Equal(t, ErrTransactionAborted, err)
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "users"))
+}
+
+func TestAdapter_Transaction_snapshot(t *testing.T) {
+	var (
+		ctx     = context.TODO()
+		adapter = New()
+		repo    = rel.New(adapter)
+	)
+
+	tx, err := adapter.Begin(ctx, rel.TransactionOptions{})
+	assert.Nil(t, err)
+
+	var (
+		txRepo      = rel.New(tx)
+		transaction = tx.(*Adapter).tx
+	)
+
+	txRepo.MustInsert(ctx, &User{Name: "alice"})
+	users := transaction.snapshot.tables["users"]
+
+	txRepo.MustInsert(ctx, &User{Name: "bob"})
+	assert.Same(t, users, transaction.snapshot.tables["users"], "table is only cloned once per transaction")
+
+	assert.Nil(t, tx.Savepoint(ctx, "sp"))
+	txRepo.MustInsert(ctx, &User{Name: "carol"})
+	assert.NotSame(t, users, transaction.snapshot.tables["users"], "table is cloned after savepoint")
+
+	assert.Nil(t, tx.Commit(ctx))
+	assert.Equal(t, 3, repo.MustCount(ctx, "users"))
+}
+
+func TestAdapter_Transaction_readOnly(t *testing.T) {
+	var (
+		repo = rel.New(New())
+	)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Insert(ctx, &User{Name: "alice"})
+	}, rel.ReadOnly())
+
+	assert.Equal(t, ErrReadOnlyTransaction, err)
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "users"))
+}
+
+func TestAdapter_Transaction_errors(t *testing.T) {
+	var (
+		ctx     = context.TODO()
+		adapter = New()
+	)
+
+	assert.Equal(t, ErrNoTransaction, adapter.Commit(ctx))
+	assert.Equal(t, ErrNoTransaction, adapter.Rollback(ctx))
+	assert.Equal(t, ErrNoTransaction, adapter.Savepoint(ctx, "sp"))
+
+	tx, err := adapter.Begin(ctx, rel.TransactionOptions{})
+	assert.Nil(t, err)
+
+	_, err = tx.Begin(ctx, rel.TransactionOptions{})
+	assert.True(t, errors.Is(err, ErrNotSupported))
+	assert.Equal(t, ErrSavepointNotFound, tx.RollbackTo(ctx, "sp"))
+	assert.Equal(t, ErrSavepointNotFound, tx.Release(ctx, "sp"))
+
+	assert.Nil(t, tx.Commit(ctx))
+	assert.Equal(t, ErrNoTransaction, tx.Commit(ctx))
+	assert.Equal(t, ErrNoTransaction, tx.Rollback(ctx))
+
+	_, err = tx.Query(ctx, rel.From("users"))
+	assert.Equal(t, ErrNoTransaction, err)
+}
+
+func TestAdapter_Apply(t *testing.T) {
+	var (
+		ctx     = context.TODO()
+		adapter = New()
+		repo    = rel.New(adapter)
+		book    = Book{Isbn: "1", Title: "Go"}
+		schema  rel.Schema
+	)
+
+	setup(t, repo)
+	repo.MustInsert(ctx, &book)
+
+	schema.RenameColumn("books", "title", "name")
+	schema.AddColumn("books", "price", rel.Int, rel.Default(100))
+	schema.CreateUniqueIndex("books", "books_name_key", []string{"name"})
+	schema.RenameTable("books", "novels")
+	schema.DropTableIfExists("unknown")
+
+	for _, migration := range schema.Migrations {
+		assert.Nil(t, adapter.Apply(ctx, migration))
+	}
+
+	var (
+		result struct {
+			ID    int
+			Isbn  string
+			Name  string
+			Price int
+		}
+	)
+
+	assert.Nil(t, repo.Find(ctx, &result, rel.From("novels")))
+	assert.Equal(t, "Go", result.Name)
+	assert.Equal(t, 100, result.Price)
+	assert.Equal(t, 0, repo.MustCount(ctx, "books"))
+
+	_, err := adapter.Insert(ctx, rel.From("novels"), "id", map[string]rel.Mutate{"name": rel.Set("name", "Go")}, rel.OnConflict{})
+	assert.True(t, errors.Is(err, rel.ConstraintError{Type: rel.UniqueConstraint, Key: "books_name_key"}))
+
+	assert.NotNil(t, adapter.Apply(ctx, rel.Table{Op: rel.SchemaCreate, Name: "novels"}))
+	assert.NotNil(t, adapter.Apply(ctx, rel.Table{Op: rel.SchemaDrop, Name: "books"}))
+	assert.True(t, errors.Is(adapter.Apply(ctx, rel.Raw("SELECT 1")), ErrNotSupported))
+}
+
+func TestAdapter_FindPage(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		users []User
+	)
+
+	page, err := repo.FindPage(context.TODO(), &users, sort.Desc("age"), rel.PageSize(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bob", "carol"}, []string{users[0].Name, users[1].Name})
+	assert.Equal(t, "", page.Prev)
+
+	page, err = repo.FindPage(context.TODO(), &users, sort.Desc("age"), rel.After(page.Next), rel.PageSize(2))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, "alice", users[0].Name)
+	assert.Equal(t, "", page.Next)
+
+	page, err = repo.FindPage(context.TODO(), &users, sort.Desc("age"), rel.Before(page.Prev), rel.PageSize(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"bob", "carol"}, []string{users[0].Name, users[1].Name})
+	assert.Equal(t, "", page.Prev)
+	assert.NotEqual(t, "", page.Next)
+}
+
+func TestAdapter_Iterate(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		it    = repo.Iterate(context.TODO(), rel.From("users"), rel.BatchSize(2))
+		names []string
+	)
+
+	defer it.Close()
+	for {
+		var user User
+		if err := it.Next(&user); err == io.EOF {
+			break
+		} else {
+			assert.Nil(t, err)
+		}
+
+		names = append(names, user.Name)
+	}
+
+	assert.Equal(t, []string{"alice", "bob", "carol"}, names)
+}
+
+func TestAdapter_IterateParallel(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		lock  sync.Mutex
+		names []string
+	)
+
+	err := repo.IterateParallel(context.TODO(), rel.From("users"), 2, func(ctx context.Context, it rel.Iterator) error {
+		for {
+			var user User
+			if err := it.Next(&user); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			lock.Lock()
+			names = append(names, user.Name)
+			lock.Unlock()
+		}
+	}, rel.BatchSize(1))
+
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob", "carol"}, names)
+}
+
+func TestAdapter_IterateParallel_transaction(t *testing.T) {
+	var (
+		repo = rel.New(New())
+		_    = seed(t, repo)
+	)
+
+	assert.Equal(t, rel.ErrParallelTransaction, repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.IterateParallel(ctx, rel.From("users"), 2, func(ctx context.Context, it rel.Iterator) error {
+			t.Fatal("fn must not be called inside transaction")
+			return nil
+		})
+	}))
+}
+
+func TestAdapter_IterateParallel_error(t *testing.T) {
+	var (
+		repo = rel.New(New())
+		_    = seed(t, repo)
+		errs = errors.New("error")
+	)
+
+	err := repo.IterateParallel(context.TODO(), rel.From("users"), 3, func(ctx context.Context, it rel.Iterator) error {
+		var user User
+		if err := it.Next(&user); err != nil {
+			return err
+		}
+
+		if user.Name == "bob" {
+			return errs
+		}
+
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, errs, err)
+}
+
+func TestAdapter_Stream(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		users = rel.NewEntityRepository[User](repo)
+		names []string
+	)
+
+	users.Stream(context.TODO(), sort.Desc("id"))(func(user User, err error) bool {
+		assert.Nil(t, err)
+		names = append(names, user.Name)
+		return len(names) < 2
+	})
+
+	assert.Equal(t, []string{"carol", "bob"}, names)
+
+	users.Stream(context.TODO(), rel.Preload("addresses"))(func(user User, err error) bool {
+		assert.Equal(t, rel.ErrStreamPreload, err)
+		return false
+	})
+}
+
+func TestAdapter_With(t *testing.T) {
+	var (
+		repo   = rel.New(New())
+		_      = seed(t, repo)
+		users  []User
+		query  = rel.With("seniors", rel.From("users").Where(where.Gte("age", 30))).From("seniors")
+		it     = repo.Iterate(context.TODO(), query, rel.BatchSize(1))
+		names  []string
+		count  int
+		err    error
+		cities []Address
+	)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users, query.SortDesc("name").Preload("addresses")))
+	assert.Len(t, users, 2)
+	assert.Equal(t, "carol", users[0].Name)
+	assert.Len(t, users[1].Addresses, 1)
+
+	count, err = repo.Aggregate(context.TODO(), query, "count", "*")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	defer it.Close()
+	for {
+		var user User
+		if err := it.Next(&user); err == io.EOF {
+			break
+		} else {
+			assert.Nil(t, err)
+		}
+
+		names = append(names, user.Name)
+	}
+
+	assert.Equal(t, []string{"bob", "carol"}, names)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &cities, rel.From("addresses").
+		With("seniors", rel.From("users").Where(where.Gte("age", 30))).
+		Where(where.In("user_id", rel.Select("id").From("seniors")))))
+	assert.Len(t, cities, 1)
+	assert.Equal(t, "Surabaya", cities[0].City)
+}
+
+func TestAdapter_WithRecursive(t *testing.T) {
+	type Category struct {
+		ID       int
+		ParentID *int
+		Name     string
+	}
+
+	var (
+		repo       = rel.New(New())
+		schema     rel.Schema
+		categories []Category
+		root       = Category{Name: "root"}
+	)
+
+	schema.CreateTable("categories", func(t *rel.Table) {
+		t.ID("id")
+		t.Int("parent_id", rel.Required(false))
+		t.String("name")
+	})
+
+	for _, migration := range schema.Migrations {
+		assert.Nil(t, repo.Adapter(context.TODO()).Apply(context.TODO(), migration))
+	}
+
+	repo.MustInsert(context.TODO(), &root)
+	child := Category{ParentID: &root.ID, Name: "child"}
+	repo.MustInsert(context.TODO(), &child)
+	repo.MustInsert(context.TODO(), &Category{ParentID: &child.ID, Name: "grandchild"})
+	repo.MustInsert(context.TODO(), &Category{Name: "other"})
+
+	query := rel.WithRecursive("tree",
+		rel.From("categories").Where(where.Eq("id", root.ID)),
+		rel.From("categories").Select("categories.*").JoinOn("tree", "tree.id", "categories.parent_id"),
+	).From("tree").SortAsc("id")
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &categories, query))
+	assert.Len(t, categories, 3)
+	assert.Equal(t, "grandchild", categories[2].Name)
+	assert.Equal(t, 3, repo.MustAggregate(context.TODO(), query, "count", "*"))
+}
+
+func TestAdapter_Combination(t *testing.T) {
+	var (
+		repo    = rel.New(New())
+		_       = seed(t, repo)
+		seniors = rel.From("users").Select("name").Where(where.Eq("age", 30))
+		bob     = rel.From("users").Select("name").Where(where.Eq("name", "bob"))
+	)
+
+	tests := []struct {
+		name   string
+		query  rel.Query
+		result []string
+	}{
+		{
+			name:   "union",
+			query:  seniors.Union(bob),
+			result: []string{"bob", "carol"},
+		},
+		{
+			name:   "union all",
+			query:  seniors.UnionAll(bob),
+			result: []string{"bob", "bob", "carol"},
+		},
+		{
+			name:   "union with other table",
+			query:  bob.Union(rel.From("addresses").Select("city").Where(where.Eq("city", "Jakarta"))),
+			result: []string{"Jakarta", "bob"},
+		},
+		{
+			name:   "intersect",
+			query:  seniors.Intersect(bob),
+			result: []string{"bob"},
+		},
+		{
+			name:   "intersect all",
+			query:  seniors.UnionAll(bob).IntersectAll(bob.UnionAll(bob)),
+			result: []string{"bob", "bob"},
+		},
+		{
+			name:   "except",
+			query:  rel.From("users").Select("name").Except(seniors),
+			result: []string{"alice"},
+		},
+		{
+			name:   "except all",
+			query:  seniors.UnionAll(bob).ExceptAll(bob),
+			result: []string{"bob", "carol"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				result []string
+				query  = test.query.SortAsc("name")
+			)
+
+			cur, err := repo.Adapter(context.TODO()).Query(context.TODO(), query)
+			assert.Nil(t, err)
+
+			for cur.Next() {
+				var name string
+				assert.Nil(t, cur.Scan(&name))
+				result = append(result, name)
+			}
+
+			assert.Equal(t, test.result, result)
+			assert.Equal(t, len(test.result), repo.MustAggregate(context.TODO(), test.query, "count", "*"))
+		})
+	}
+}
+
+func TestAdapter_Combination_findAll(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		users []User
+	)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users,
+		rel.Where(where.Eq("name", "carol")).Union(rel.Where(where.Eq("name", "alice"))).SortDesc("name").Limit(1)))
+	assert.Len(t, users, 1)
+	assert.Equal(t, "carol", users[0].Name)
+	assert.Equal(t, 30, users[0].Age)
+
+	_, err := repo.Adapter(context.TODO()).Query(context.TODO(), rel.From("users").CombineWith("MINUS", rel.From("users")))
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestAdapter_SelectExpr(t *testing.T) {
+	type Ranked struct {
+		ID      int
+		Name    string
+		Age     int
+		Rank    int    `db:"rank,computed"`
+		Dense   int    `db:"dense,computed"`
+		Number  int    `db:"number,computed"`
+		Running int    `db:"running,computed"`
+		Total   int    `db:"total,computed"`
+		Group   string `db:"group,computed"`
+		Upper   string `db:"upper,computed"`
+	}
+
+	var (
+		repo   = rel.New(New())
+		_      = seed(t, repo)
+		ranked []Ranked
+	)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &ranked, rel.From("users").SelectExpr(
+		rel.Fn("rank").Over().OrderBy(rel.SortDesc("age")).As("rank"),
+		rel.Fn("dense_rank").Over().OrderBy(rel.SortDesc("age")).As("dense"),
+		rel.Fn("row_number").Over().PartitionBy("age").OrderBy(rel.SortDesc("name")).As("number"),
+		rel.Fn("sum", rel.Field("age")).Over().OrderBy(rel.SortAsc("age")).As("running"),
+		rel.Fn("sum", rel.Field("age")).Over().As("total"),
+		rel.Case(rel.When(where.Gte("age", 30), rel.Value("senior"))).Else(rel.Value("junior")).As("group"),
+		rel.Fn("upper", rel.Field("name")).As("upper"),
+	).SortAsc("id")))
+
+	assert.Equal(t, []Ranked{
+		{ID: 1, Name: "alice", Age: 20, Rank: 3, Dense: 2, Number: 1, Running: 20, Total: 80, Group: "junior", Upper: "ALICE"},
+		{ID: 2, Name: "bob", Age: 30, Rank: 1, Dense: 1, Number: 2, Running: 80, Total: 80, Group: "senior", Upper: "BOB"},
+		{ID: 3, Name: "carol", Age: 30, Rank: 1, Dense: 1, Number: 1, Running: 80, Total: 80, Group: "senior", Upper: "CAROL"},
+	}, ranked)
+}
+
+func TestAdapter_SelectExpr_aggregate(t *testing.T) {
+	type Count struct {
+		Age   int
+		Count int
+		Names int
+	}
+
+	var (
+		repo   = rel.New(New())
+		_      = seed(t, repo)
+		counts []Count
+	)
+
+	cur, err := repo.Adapter(context.TODO()).Query(context.TODO(), rel.From("users").Select("age").SelectExpr(
+		rel.Fn("count", rel.Field("*")).As("count"),
+		rel.Fn("count", rel.Field("name")).Distinct().As("names"),
+		rel.Fn("rank").Over().OrderBy(rel.SortDesc("count")).As("rank"),
+	).Group("age").SortDesc("count"))
+	assert.Nil(t, err)
+
+	for cur.Next() {
+		var c Count
+		var rank int
+		assert.Nil(t, cur.Scan(&c.Age, &c.Count, &c.Names, &rank))
+		assert.Equal(t, map[int]int{30: 1, 20: 2}[c.Age], rank)
+		counts = append(counts, c)
+	}
+
+	assert.Equal(t, []Count{{Age: 30, Count: 2, Names: 2}, {Age: 20, Count: 1, Names: 1}}, counts)
+}
+
+func TestAdapter_Match(t *testing.T) {
+	type Article struct {
+		ID    int
+		Title string
+		Body  string
+		Score float64 `db:"score,computed"`
+	}
+
+	var (
+		repo     = rel.New(New())
+		fields   = []string{"title", "body"}
+		articles = []Article{
+			{Title: "Go ORM", Body: "REL is a golang orm"},
+			{Title: "Rust", Body: "Rust has no garbage collector"},
+			{Title: "Building an ORM in Go", Body: "Go, go, go!"},
+		}
+	)
+
+	for i := range articles {
+		assert.Nil(t, repo.Insert(context.TODO(), &articles[i]))
+	}
+
+	var result []Article
+	assert.Nil(t, repo.FindAll(context.TODO(), &result, where.Match(fields, "GO orm"), sort.Rank(fields, "go orm"), rel.SelectExpr(rel.Rank(fields, "go orm").As("score"))))
+	assert.Len(t, result, 2)
+	assert.Equal(t, "Building an ORM in Go", result[0].Title)
+	assert.Equal(t, float64(5), result[0].Score)
+	assert.Equal(t, "Go ORM", result[1].Title)
+	assert.Equal(t, float64(3), result[1].Score)
+
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "articles", where.Not(where.Match(fields, "orm"))))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "articles", where.Match(fields, "")))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "articles", where.Match(fields, "go java")))
+}
+
+func TestAdapter_Tenant(t *testing.T) {
+	type Invoice struct {
+		ID       int
+		TenantID int `db:"tenant_id,tenant"`
+		Amount   int
+	}
+
+	rel.RegisterTenant(Invoice{})
+
+	var (
+		repo = rel.New(New())
+		acme = rel.WithTenant(context.TODO(), 1)
+		umbr = rel.WithTenant(context.TODO(), 2)
+	)
+
+	assert.Nil(t, repo.Insert(acme, &Invoice{Amount: 10}))
+	assert.Nil(t, repo.InsertAll(acme, &[]Invoice{{Amount: 20}, {Amount: 30}}))
+	assert.Nil(t, repo.Insert(umbr, &Invoice{Amount: 40}))
+
+	var invoices []Invoice
+	assert.Nil(t, repo.FindAll(umbr, &invoices))
+	assert.Equal(t, []Invoice{{ID: 4, TenantID: 2, Amount: 40}}, invoices)
+	assert.Equal(t, 3, repo.MustCount(acme, "invoices"))
+	assert.Equal(t, 4, repo.MustCount(context.TODO(), "invoices"))
+
+	var invoice Invoice
+	assert.Equal(t, rel.ErrNotFound, repo.Find(umbr, &invoice, where.Eq("id", 1)))
+
+	_, err := repo.DeleteAny(context.TODO(), rel.From("invoices"))
+	assert.Equal(t, rel.ErrTenantRequired, err)
+
+	deleted, err := repo.DeleteAny(umbr, rel.From("invoices"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "invoices"))
+}
+
+func TestAdapter_Tenant_delete(t *testing.T) {
+	type Contract struct {
+		ID       int
+		TenantID int `db:"tenant_id,tenant"`
+	}
+
+	var (
+		repo      = rel.New(New())
+		acme      = rel.WithTenant(context.TODO(), 1)
+		umbr      = rel.WithTenant(context.TODO(), 2)
+		contracts = []Contract{{ID: 1}, {ID: 2}}
+	)
+
+	assert.Nil(t, repo.Insert(acme, &contracts[0]))
+	assert.Nil(t, repo.Insert(acme, &contracts[1]))
+
+	assert.Equal(t, rel.ErrNotFound, repo.Delete(umbr, &contracts[0]))
+	assert.Nil(t, repo.DeleteAll(umbr, &contracts))
+	assert.Equal(t, 2, repo.MustCount(acme, "contracts"))
+
+	_, err := repo.DeleteAny(context.TODO(), rel.From("contracts"))
+	assert.Equal(t, rel.ErrTenantRequired, err)
+
+	assert.Nil(t, repo.DeleteAll(acme, &contracts))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "contracts"))
+}
+
+type Post struct {
+	ID       int
+	Title    string
+	Comments []Comment `polymorphic:"commentable" autosave:"true"`
+}
+
+type Photo struct {
+	ID       int
+	Comments []Comment `polymorphic:"commentable" autosave:"true"`
+}
+
+type Comment struct {
+	ID              int
+	CommentableID   int
+	CommentableType string
+	Body            string
+	Commentable     any `polymorphic:"commentable"`
+}
+
+func TestAdapter_Polymorphic(t *testing.T) {
+	rel.RegisterPolymorphic(Post{}, Photo{})
+
+	var (
+		repo  = rel.New(New())
+		post  = Post{Title: "rel", Comments: []Comment{{Body: "nice"}, {Body: "great"}}}
+		photo = Photo{Comments: []Comment{{Body: "wow"}}}
+	)
+
+	assert.Nil(t, repo.Insert(context.TODO(), &post))
+	assert.Nil(t, repo.Insert(context.TODO(), &photo))
+	assert.Equal(t, 1, photo.ID)
+	assert.Equal(t, "photos", photo.Comments[0].CommentableType)
+
+	var result Post
+	assert.Nil(t, repo.Find(context.TODO(), &result, where.Eq("id", 1)))
+	assert.Nil(t, repo.Preload(context.TODO(), &result, "comments"))
+	assert.Len(t, result.Comments, 2)
+
+	var comments []Comment
+	assert.Nil(t, repo.FindAll(context.TODO(), &comments, rel.NewSortAsc("id")))
+	assert.Nil(t, repo.Preload(context.TODO(), &comments, "commentable"))
+	assert.Equal(t, &Post{ID: 1, Title: "rel"}, comments[0].Commentable)
+	assert.Equal(t, &Post{ID: 1, Title: "rel"}, comments[1].Commentable)
+	assert.Equal(t, &Photo{ID: 1}, comments[2].Commentable)
+
+	var posts []Post
+	assert.Nil(t, repo.FindAll(context.TODO(), &posts, where.HasAssoc("comments", where.Eq("comments.body", "wow"))))
+	assert.Len(t, posts, 0)
+
+	assert.Nil(t, repo.Delete(context.TODO(), &photo, rel.Cascade(true)))
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "comments"))
+}
+
+func TestAdapter_HasManyThrough(t *testing.T) {
+	type Course struct {
+		ID   int
+		Name string
+	}
+
+	type StudentCourse struct {
+		StudentID int `db:",primary"`
+		CourseID  int `db:",primary"`
+	}
+
+	type Student struct {
+		ID             int
+		Name           string
+		StudentCourses []StudentCourse
+		Courses        []Course `through:"student_courses" autosave:"true"`
+	}
+
+	var (
+		repo    = rel.New(New())
+		math    = Course{Name: "math"}
+		student = Student{Name: "alice", Courses: []Course{{Name: "physics"}}}
+	)
+
+	assert.Nil(t, repo.Insert(context.TODO(), &math))
+	student.Courses = append(student.Courses, math)
+	assert.Nil(t, repo.Insert(context.TODO(), &student))
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "courses"))
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "student_courses"))
+
+	changeset := rel.NewChangeset(&student)
+	student.Courses = []Course{math, {Name: "biology"}}
+	assert.Nil(t, repo.Update(context.TODO(), &student, changeset))
+
+	var links []StudentCourse
+	assert.Nil(t, repo.FindAll(context.TODO(), &links, rel.NewSortAsc("course_id")))
+	assert.Equal(t, []StudentCourse{{StudentID: 1, CourseID: 1}, {StudentID: 1, CourseID: 3}}, links)
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "courses"))
+
+	assert.Nil(t, repo.Delete(context.TODO(), &student, rel.Cascade(true)))
+	assert.Equal(t, 0, repo.MustCount(context.TODO(), "student_courses"))
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "courses"))
+}
+
+func TestAdapter_PreloadLimit(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		users []User
+	)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users, rel.NewSortAsc("id")))
+	assert.Nil(t, repo.Preload(context.TODO(), &users, "addresses", rel.NewSortDesc("id"), rel.PreloadLimit(1)))
+	assert.Equal(t, []Address{{ID: 2, UserID: 1, City: "Bandung"}}, users[0].Addresses)
+	assert.Equal(t, []Address{{ID: 3, UserID: 2, City: "Surabaya"}}, users[1].Addresses)
+	assert.Len(t, users[2].Addresses, 0)
+
+	var addresses []Address
+	assert.Nil(t, repo.FindAll(context.TODO(), &addresses, rel.From("addresses").SortAsc("id").PartitionLimit("user_id", 1)))
+	assert.Equal(t, []Address{{ID: 1, UserID: 1, City: "Jakarta"}, {ID: 3, UserID: 2, City: "Surabaya"}}, addresses)
+}
+
+func TestAdapter_PreloadCount(t *testing.T) {
+	type Article struct {
+		ID        int
+		Title     string
+		AuthorID  int
+		DeletedAt *time.Time
+	}
+
+	type Author struct {
+		ID            int
+		Name          string
+		Articles      []Article `autosave:"true"`
+		ArticlesCount int       `db:"articles_count,count:articles"`
+	}
+
+	var (
+		repo    = rel.New(New())
+		authors = []Author{
+			{Name: "alice", Articles: []Article{{Title: "a"}, {Title: "b"}, {Title: "c"}}},
+			{Name: "bob", Articles: []Article{{Title: "d"}}},
+			{Name: "carol"},
+		}
+	)
+
+	for i := range authors {
+		assert.Nil(t, repo.Insert(context.TODO(), &authors[i]))
+	}
+
+	assert.Nil(t, repo.Delete(context.TODO(), &authors[0].Articles[0]))
+
+	var result []Author
+	assert.Nil(t, repo.FindAll(context.TODO(), &result, rel.NewSortAsc("id")))
+	assert.Nil(t, repo.PreloadCount(context.TODO(), &result, "articles"))
+	assert.Equal(t, 2, result[0].ArticlesCount)
+	assert.Equal(t, 1, result[1].ArticlesCount)
+	assert.Equal(t, 0, result[2].ArticlesCount)
+	assert.Len(t, result[0].Articles, 0)
+
+	assert.Nil(t, repo.PreloadCount(context.TODO(), &result[0], "articles", where.Eq("title", "b")))
+	assert.Equal(t, 1, result[0].ArticlesCount)
+}
+
+func TestAdapter_Batched(t *testing.T) {
+	var (
+		repo    = rel.Batched(rel.New(New()), 5*time.Millisecond)
+		_       = seed(t, repo)
+		users   = make([]User, 3)
+		errs    = make([]error, 3)
+		wg      sync.WaitGroup
+		address Address
+	)
+
+	for i := range users {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Find(context.TODO(), &users[i], where.Eq("id", i+1))
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, name := range []string{"alice", "bob", "carol"} {
+		assert.Nil(t, errs[i])
+		assert.Equal(t, name, users[i].Name)
+	}
+
+	assert.Nil(t, repo.Find(context.TODO(), &address, where.Eq("user_id", 2)))
+	assert.Equal(t, "Surabaya", address.City)
+
+	err := errors.New("rollback")
+	assert.Equal(t, err, repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		repo.MustInsert(ctx, &User{Name: "dave"})
+
+		var user User
+		assert.Nil(t, repo.Find(ctx, &user, where.Eq("id", 4)))
+		assert.Equal(t, "dave", user.Name)
+
+		return err
+	}))
+
+	assert.Equal(t, rel.NotFoundError{}, repo.Find(context.TODO(), &User{}, where.Eq("id", 4)))
+}
+
+func TestAdapter_SelectExpr_unsupported(t *testing.T) {
+	var (
+		repo    = rel.New(New())
+		_       = seed(t, repo)
+		adapter = repo.Adapter(context.TODO())
+	)
+
+	for _, expr := range []rel.SelectExpression{
+		rel.Fn("now").As("now"),
+		rel.Fn("ntile", rel.Value(2)).Over().As("tile"),
+		rel.Fn("sum", rel.Value(1)).As("sum"),
+	} {
+		_, err := adapter.Query(context.TODO(), rel.From("users").SelectExpr(expr))
+		assert.ErrorIs(t, err, ErrNotSupported)
+	}
+}
+
+func TestAdapter_Exists(t *testing.T) {
+	var (
+		repo  = rel.New(New())
+		_     = seed(t, repo)
+		users []User
+	)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users, where.Exists(
+		rel.From("addresses").Where(where.Eq("addresses.user_id", rel.Field("users.id")), where.Eq("city", "Surabaya")),
+	)))
+	assert.Len(t, users, 1)
+	assert.Equal(t, "bob", users[0].Name)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users, where.NotExists(
+		rel.From("addresses").Where(where.Eq("user_id", rel.Field("users.id"))),
+	)))
+	assert.Len(t, users, 1)
+	assert.Equal(t, "carol", users[0].Name)
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &users, where.Gte("age",
+		rel.From("users as u").Select("max(u.age)").Where(where.Lt("u.id", rel.Field("users.id"))),
+	), sort.Asc("id")))
+	assert.Len(t, users, 2)
+	assert.Equal(t, "bob", users[0].Name)
+	assert.Equal(t, "carol", users[1].Name)
+}
+
+func TestAdapter_HasAssoc(t *testing.T) {
+	var (
+		repo     = rel.New(New())
+		_        = seed(t, repo)
+		entities = rel.NewEntityRepository[User](repo)
+		names    []string
+	)
+
+	users := entities.MustFindAll(context.TODO(), where.HasAssoc("addresses", where.Like("addresses.city", "Band%")))
+	assert.Len(t, users, 1)
+	assert.Equal(t, "alice", users[0].Name)
+
+	users = entities.MustFindAll(context.TODO(), where.NotHasAssoc("addresses"))
+	assert.Len(t, users, 1)
+	assert.Equal(t, "carol", users[0].Name)
+
+	assert.Equal(t, 2, entities.MustAggregate(context.TODO(), "count", "*", where.HasAssoc("addresses")))
+
+	it := entities.Iterate(context.TODO(), rel.Where(where.HasAssoc("addresses")), rel.BatchSize(1))
+	defer it.Close()
+	for {
+		user, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+
+		assert.Nil(t, err)
+		names = append(names, user.Name)
+	}
+
+	assert.Equal(t, []string{"alice", "bob"}, names)
+
+	_, err := repo.Adapter(context.TODO()).Query(context.TODO(), rel.From("users").Where(where.HasAssoc("addresses")))
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
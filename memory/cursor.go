@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/go-rel/rel"
+)
+
+type cursor struct {
+	fields  []string
+	records [][]any
+	current int
+}
+
+var _ rel.Cursor = (*cursor)(nil)
+
+func (c *cursor) Close() error {
+	c.records = nil
+	return nil
+}
+
+func (c *cursor) Fields() ([]string, error) {
+	return c.fields, nil
+}
+
+func (c *cursor) Next() bool {
+	if c.current >= len(c.records) {
+		return false
+	}
+
+	c.current++
+	return true
+}
+
+func (c *cursor) Scan(dest ...any) error {
+	if c.current == 0 || c.current > len(c.records) {
+		return sql.ErrNoRows
+	}
+
+	var (
+		values = c.records[c.current-1]
+	)
+
+	if len(dest) != len(values) {
+		return fmt.Errorf("memory: expected %d destination arguments in Scan, not %d", len(values), len(dest))
+	}
+
+	for i := range dest {
+		if err := scan(dest[i], values[i]); err != nil {
+			return fmt.Errorf("memory: scan field %s: %w", c.fields[i], err)
+		}
+	}
+
+	return nil
+}
+
+func (c *cursor) NopScanner() any {
+	return &sql.RawBytes{}
+}
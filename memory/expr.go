@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/go-rel/rel"
+)
+
+func isAggregate(name string) bool {
+	switch strings.ToLower(name) {
+	case "count", "sum", "avg", "min", "max":
+		return true
+	}
+
+	return false
+}
+
+// aggregateField returns field to be aggregated by function, only a single field argument is supported.
+func aggregateField(fe rel.FuncExpr) (string, error) {
+	switch len(fe.Args) {
+	case 0:
+		return "*", nil
+	case 1:
+		if field, ok := fe.Args[0].(rel.FieldExpr); ok {
+			return string(field), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s arguments", ErrNotSupported, fe.Name)
+}
+
+// eval evaluates expression against a record or a group of records.
+func (e executor) eval(expr rel.Expr, v valuer) (any, error) {
+	switch expr := expr.(type) {
+	case rel.FieldExpr:
+		return e.field(v, string(expr)), nil
+	case rel.ValueExpr:
+		return normalize(expr.Value), nil
+	case rel.FuncExpr:
+		return e.call(expr, v)
+	case rel.MatchExpr:
+		score, _ := e.rank(expr, v)
+		return score, nil
+	case rel.CaseExpr:
+		for _, we := range expr.Whens {
+			if ok, err := e.match(we.Filter, v); err != nil {
+				return nil, err
+			} else if ok {
+				return e.eval(we.Then, v)
+			}
+		}
+
+		if expr.Default != nil {
+			return e.eval(expr.Default, v)
+		}
+
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s expression", ErrNotSupported, expr)
+}
+
+func (e executor) call(fe rel.FuncExpr, v valuer) (any, error) {
+	name := strings.ToLower(fe.Name)
+
+	if isAggregate(name) {
+		field, err := aggregateField(fe)
+		if err != nil {
+			return nil, err
+		}
+
+		switch records := v.(type) {
+		case group:
+			return aggregate(name, field, fe.OnlyDistinct, records), nil
+		case record:
+			return aggregate(name, field, fe.OnlyDistinct, []record{records}), nil
+		}
+	}
+
+	args := make([]any, len(fe.Args))
+	for i := range fe.Args {
+		var err error
+		if args[i], err = e.eval(fe.Args[i], v); err != nil {
+			return nil, err
+		}
+	}
+
+	switch name {
+	case "coalesce":
+		for i := range args {
+			if args[i] != nil {
+				return args[i], nil
+			}
+		}
+
+		return nil, nil
+	case "lower", "upper":
+		if len(args) != 1 || args[0] == nil {
+			return nil, nil
+		}
+
+		str := fmt.Sprint(args[0])
+		if b, ok := args[0].([]byte); ok {
+			str = string(b)
+		}
+
+		if name == "lower" {
+			return strings.ToLower(str), nil
+		}
+
+		return strings.ToUpper(str), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s function", ErrNotSupported, fe.Name)
+}
+
+// words splits text into lower cased words.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// rank returns number of query words occurrences in the fields,
+// second return value is true when every word of the query is found.
+func (e executor) rank(me rel.MatchExpr, v valuer) (float64, bool) {
+	var (
+		terms  = words(me.Query)
+		counts = make(map[string]int, len(terms))
+		score  float64
+	)
+
+	for _, term := range terms {
+		counts[term] = 0
+	}
+
+	for _, field := range me.Fields {
+		var text string
+		switch value := v.value(field).(type) {
+		case string:
+			text = value
+		case []byte:
+			text = string(value)
+		default:
+			continue
+		}
+
+		for _, word := range words(text) {
+			if count, ok := counts[word]; ok {
+				counts[word] = count + 1
+				score++
+			}
+		}
+	}
+
+	for _, count := range counts {
+		if count == 0 {
+			return score, false
+		}
+	}
+
+	return score, len(terms) > 0
+}
+
+// window evaluates window function for every item, and stores the result in the given column.
+func (e executor) window(we rel.WindowExpr, items []item, column int) error {
+	var (
+		name       = strings.ToLower(we.Func.Name)
+		partitions = make(map[string][]int)
+		keys       []string
+	)
+
+	for i := range items {
+		values := make([]any, len(we.Partition))
+		for j := range we.Partition {
+			values[j] = items[i].value(we.Partition[j])
+		}
+
+		k := key(values...)
+		if _, ok := partitions[k]; !ok {
+			keys = append(keys, k)
+		}
+
+		partitions[k] = append(partitions[k], i)
+	}
+
+	// compare returns 0 when both items are peers in the window ordering.
+	compare := func(a, b int) int {
+		for _, sq := range we.SortQuery {
+			if c := less(items[a].value(sq.Field), items[b].value(sq.Field)); c != 0 {
+				if sq.Desc() {
+					return -c
+				}
+
+				return c
+			}
+		}
+
+		return 0
+	}
+
+	for _, k := range keys {
+		indexes := partitions[k]
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return compare(indexes[i], indexes[j]) < 0
+		})
+
+		switch {
+		case name == "row_number":
+			for pos, i := range indexes {
+				items[i].values[column] = int64(pos + 1)
+			}
+		case name == "rank" || name == "dense_rank":
+			var rank, dense int64
+			for pos, i := range indexes {
+				if pos == 0 || compare(indexes[pos-1], i) != 0 {
+					rank = int64(pos + 1)
+					dense++
+				}
+
+				if name == "rank" {
+					items[i].values[column] = rank
+				} else {
+					items[i].values[column] = dense
+				}
+			}
+		case isAggregate(name):
+			field, err := aggregateField(we.Func)
+			if err != nil {
+				return err
+			}
+
+			records := make([]record, len(indexes))
+			for pos, i := range indexes {
+				records[pos] = record{field: items[i].value(field)}
+			}
+
+			// without ordering, the frame is the whole partition, otherwise it's all rows up to the last peer of current row.
+			for pos, i := range indexes {
+				end := len(indexes)
+				if len(we.SortQuery) > 0 {
+					end = pos + 1
+					for end < len(indexes) && compare(indexes[end], i) == 0 {
+						end++
+					}
+				}
+
+				items[i].values[column] = aggregate(name, field, we.Func.OnlyDistinct, records[:end])
+			}
+		default:
+			return fmt.Errorf("%w: %s window function", ErrNotSupported, we.Func.Name)
+		}
+	}
+
+	return nil
+}
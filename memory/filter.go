@@ -0,0 +1,341 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rel/rel"
+)
+
+// match returns true if valuer satisfies the filter.
+// Comparison involving nil is never satisfied, just like comparison with NULL in sql.
+func (e executor) match(filter rel.FilterQuery, v valuer) (bool, error) {
+	switch filter.Type {
+	case rel.FilterAndOp:
+		for i := range filter.Inner {
+			if ok, err := e.match(filter.Inner[i], v); !ok || err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case rel.FilterOrOp:
+		for i := range filter.Inner {
+			if ok, err := e.match(filter.Inner[i], v); ok || err != nil {
+				return ok, err
+			}
+		}
+
+		return len(filter.Inner) == 0, nil
+	case rel.FilterNotOp:
+		if len(filter.Inner) == 0 {
+			return true, nil
+		}
+
+		ok, err := e.match(rel.And(filter.Inner...), v)
+		return !ok && err == nil, err
+	case rel.FilterEqOp, rel.FilterNeOp, rel.FilterLtOp, rel.FilterLteOp, rel.FilterGtOp, rel.FilterGteOp:
+		return e.compare(filter, v)
+	case rel.FilterNilOp:
+		return v.value(filter.Field) == nil, nil
+	case rel.FilterNotNilOp:
+		return v.value(filter.Field) != nil, nil
+	case rel.FilterInOp, rel.FilterNinOp:
+		return e.in(filter, v.value(filter.Field))
+	case rel.FilterExistsOp, rel.FilterNotExistsOp:
+		sub, ok := filter.Value.(rel.Query)
+		if !ok || filter.Field != "" {
+			return false, fmt.Errorf("%w: unresolved %s filter", ErrNotSupported, filter.Type)
+		}
+
+		cur, err := e.correlate(v).query(sub)
+		if err != nil {
+			return false, err
+		}
+
+		return (len(cur.records) > 0) == (filter.Type == rel.FilterExistsOp), nil
+	case rel.FilterLikeOp, rel.FilterNotLikeOp:
+		var (
+			value      = v.value(filter.Field)
+			pattern, _ = filter.Value.(string)
+		)
+
+		if value == nil {
+			return false, nil
+		}
+
+		return like(value, pattern) == (filter.Type == rel.FilterLikeOp), nil
+	case rel.FilterILikeOp, rel.FilterNotILikeOp:
+		var (
+			value      = v.value(filter.Field)
+			pattern, _ = filter.Value.(string)
+		)
+
+		if value == nil {
+			return false, nil
+		}
+
+		return like(lower(value), strings.ToLower(pattern)) == (filter.Type == rel.FilterILikeOp), nil
+	case rel.FilterRegexpOp, rel.FilterNotRegexpOp:
+		var (
+			value      = v.value(filter.Field)
+			pattern, _ = filter.Value.(string)
+		)
+
+		if value == nil {
+			return false, nil
+		}
+
+		matched, err := matchRegexp(value, pattern)
+		return matched == (filter.Type == rel.FilterRegexpOp) && err == nil, err
+	case rel.FilterBetweenOp, rel.FilterNotBetweenOp:
+		return between(filter, v.value(filter.Field))
+	case rel.FilterContainsOp, rel.FilterContainedByOp, rel.FilterOverlapsOp:
+		return contains(filter, v.value(filter.Field))
+	case rel.FilterJSONEqOp, rel.FilterJSONNeOp:
+		args, _ := filter.Value.([]any)
+		if len(args) != 2 {
+			return false, fmt.Errorf("memory: %s filter requires path and value", filter.Type)
+		}
+
+		path, _ := args[0].(string)
+		value, ok, err := extractJSON(v.value(filter.Field), path)
+		if !ok || err != nil {
+			return false, err
+		}
+
+		if filter.Type == rel.FilterJSONEqOp {
+			return compareOp(rel.FilterEqOp, value, normalize(args[1])), nil
+		}
+
+		return compareOp(rel.FilterNeOp, value, normalize(args[1])), nil
+	case rel.FilterMatchOp:
+		me, ok := filter.Value.(rel.MatchExpr)
+		if !ok {
+			return false, fmt.Errorf("memory: %s filter requires match expression", filter.Type)
+		}
+
+		_, matched := e.rank(me, v)
+		return matched, nil
+	case rel.FilterJSONHasKeyOp, rel.FilterJSONNotHasKeyOp:
+		var (
+			value   = v.value(filter.Field)
+			path, _ = filter.Value.(string)
+		)
+
+		if value == nil {
+			return false, nil
+		}
+
+		_, ok, err := extractJSON(value, path)
+		return ok == (filter.Type == rel.FilterJSONHasKeyOp) && err == nil, err
+	}
+
+	return false, rel.UnsupportedFilterError{Op: filter.Type, Err: ErrNotSupported}
+}
+
+func between(filter rel.FilterQuery, value any) (bool, error) {
+	bounds, ok := filter.Value.([]any)
+	if !ok || len(bounds) != 2 {
+		return false, fmt.Errorf("memory: %s filter requires lower and upper value", filter.Type)
+	}
+
+	var (
+		lower = normalize(bounds[0])
+		upper = normalize(bounds[1])
+	)
+
+	if value == nil || lower == nil || upper == nil {
+		return false, nil
+	}
+
+	lc, lok := compare(value, lower)
+	uc, uok := compare(value, upper)
+	if !lok || !uok {
+		return false, nil
+	}
+
+	return (lc >= 0 && uc <= 0) == (filter.Type == rel.FilterBetweenOp), nil
+}
+
+// contains only supports slice and array value, json containment is not supported.
+func contains(filter rel.FilterQuery, value any) (bool, error) {
+	right, ok := elements(filter.Value)
+	if !ok {
+		return false, rel.UnsupportedFilterError{Op: filter.Type, Err: ErrNotSupported}
+	}
+
+	if value == nil {
+		return false, nil
+	}
+
+	left, ok := elements(value)
+	if !ok {
+		return false, rel.UnsupportedFilterError{Op: filter.Type, Err: ErrNotSupported}
+	}
+
+	switch filter.Type {
+	case rel.FilterContainsOp:
+		return subset(right, left), nil
+	case rel.FilterContainedByOp:
+		return subset(left, right), nil
+	default:
+		for i := range left {
+			for j := range right {
+				if equal(left[i], right[j]) {
+					return true, nil
+				}
+			}
+		}
+
+		return false, nil
+	}
+}
+
+// subset returns true if every element of a exists in b.
+func subset(a []any, b []any) bool {
+	for i := range a {
+		found := false
+		for j := range b {
+			if equal(a[i], b[j]) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e executor) compare(filter rel.FilterQuery, v valuer) (bool, error) {
+	left := v.value(filter.Field)
+
+	switch value := filter.Value.(type) {
+	case rel.FieldExpr:
+		return compareOp(filter.Type, left, e.field(v, string(value))), nil
+	case rel.Query:
+		values, err := e.correlate(v).column(value)
+		if err != nil || len(values) == 0 {
+			return false, err
+		}
+
+		return compareOp(filter.Type, left, values[0]), nil
+	case rel.SubQuery:
+		values, err := e.correlate(v).column(value.Query)
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToUpper(value.Prefix) {
+		case "ALL":
+			for i := range values {
+				if !compareOp(filter.Type, left, values[i]) {
+					return false, nil
+				}
+			}
+
+			return true, nil
+		case "ANY", "SOME":
+			for i := range values {
+				if compareOp(filter.Type, left, values[i]) {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%w: %s sub query", ErrNotSupported, value.Prefix)
+	default:
+		return compareOp(filter.Type, left, normalize(value)), nil
+	}
+}
+
+func compareOp(op rel.FilterOp, left any, right any) bool {
+	if left == nil || right == nil {
+		return false
+	}
+
+	c, ok := compare(left, right)
+	if !ok {
+		return op == rel.FilterNeOp
+	}
+
+	switch op {
+	case rel.FilterEqOp:
+		return c == 0
+	case rel.FilterNeOp:
+		return c != 0
+	case rel.FilterLtOp:
+		return c < 0
+	case rel.FilterLteOp:
+		return c <= 0
+	case rel.FilterGtOp:
+		return c > 0
+	case rel.FilterGteOp:
+		return c >= 0
+	}
+
+	return false
+}
+
+func (e executor) in(filter rel.FilterQuery, left any) (bool, error) {
+	if left == nil {
+		return false, nil
+	}
+
+	values, err := e.values(filter.Value)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		hasNil bool
+	)
+
+	for i := range values {
+		if values[i] == nil {
+			hasNil = true
+		} else if equal(left, values[i]) {
+			return filter.Type == rel.FilterInOp, nil
+		}
+	}
+
+	// NOT IN with nil in the list is never satisfied.
+	return filter.Type == rel.FilterNinOp && !hasNil, nil
+}
+
+// values of in filter, sub query is evaluated and its first column is used as values.
+func (e executor) values(value any) ([]any, error) {
+	var (
+		items  []any
+		values []any
+	)
+
+	switch v := value.(type) {
+	case []any:
+		items = v
+	default:
+		items = []any{v}
+	}
+
+	for i := range items {
+		switch item := items[i].(type) {
+		case rel.Query:
+			sub, err := e.column(item)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, sub...)
+		default:
+			values = append(values, normalize(item))
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePath parses json path such as $.address.city or $.tags[0] into keys and indexes.
+// The root selector ($) is optional.
+func parsePath(path string) ([]any, error) {
+	var (
+		segments []any
+		rest     = strings.TrimPrefix(path, "$")
+	)
+
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+
+			if end == 1 {
+				return nil, fmt.Errorf("memory: invalid json path %s", path)
+			}
+
+			segments = append(segments, rest[1:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("memory: invalid json path %s", path)
+			}
+
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("memory: invalid json path %s", path)
+			}
+
+			segments = append(segments, index)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("memory: invalid json path %s", path)
+		}
+	}
+
+	return segments, nil
+}
+
+// decodeJSON decodes json column, value that is not encoded is converted through json so it has the same representation.
+func decodeJSON(value any) (any, error) {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return toJSON(v)
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// toJSON converts value to its json representation, string value is encoded as json string.
+func toJSON(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func encodeJSON(doc any) (any, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// extractJSON returns normalized value at the path, second return value is false if path doesn't exist.
+func extractJSON(value any, path string) (any, bool, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	doc, err := decodeJSON(value)
+	if err != nil || value == nil {
+		return nil, false, err
+	}
+
+	for _, segment := range segments {
+		switch s := segment.(type) {
+		case string:
+			obj, ok := doc.(map[string]any)
+			if !ok {
+				return nil, false, nil
+			}
+
+			if doc, ok = obj[s]; !ok {
+				return nil, false, nil
+			}
+		case int:
+			arr, ok := doc.([]any)
+			if !ok || s >= len(arr) {
+				return nil, false, nil
+			}
+
+			doc = arr[s]
+		}
+	}
+
+	return normalize(doc), true, nil
+}
+
+func setJSON(doc any, segments []any, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	switch s := segments[0].(type) {
+	case string:
+		obj, ok := doc.(map[string]any)
+		if doc == nil {
+			obj, ok = make(map[string]any), true
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("memory: cannot set json key %s of non object", s)
+		}
+
+		v, err := setJSON(obj[s], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		obj[s] = v
+		return obj, nil
+	default:
+		index := s.(int)
+		arr, ok := doc.([]any)
+		if !ok || index > len(arr) {
+			return nil, fmt.Errorf("memory: cannot set json index %d", index)
+		}
+
+		if index == len(arr) {
+			arr = append(arr, nil)
+		}
+
+		v, err := setJSON(arr[index], segments[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		arr[index] = v
+		return arr, nil
+	}
+}
+
+func removeJSON(doc any, segments []any) any {
+	if len(segments) == 0 {
+		return doc
+	}
+
+	last := len(segments) - 1
+	switch s := segments[0].(type) {
+	case string:
+		if obj, ok := doc.(map[string]any); ok {
+			if last == 0 {
+				delete(obj, s)
+			} else if v, ok := obj[s]; ok {
+				obj[s] = removeJSON(v, segments[1:])
+			}
+		}
+	case int:
+		if arr, ok := doc.([]any); ok && s < len(arr) {
+			if last == 0 {
+				return append(arr[:s], arr[s+1:]...)
+			}
+
+			arr[s] = removeJSON(arr[s], segments[1:])
+		}
+	}
+
+	return doc
+}
+
+// mutateJSON applies json set or remove to the column value, result is stored encoded.
+func mutateJSON(value any, path string, set bool, arg any) (any, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeJSON(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if set {
+		if arg, err = toJSON(arg); err != nil {
+			return nil, err
+		}
+
+		if doc, err = setJSON(doc, segments, arg); err != nil {
+			return nil, err
+		}
+	} else {
+		if value == nil {
+			return nil, nil
+		}
+
+		doc = removeJSON(doc, segments)
+	}
+
+	return encodeJSON(doc)
+}
@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rel/rel"
+)
+
+func migrate(s *snapshot, migration rel.Migration) error {
+	switch v := migration.(type) {
+	case rel.Table:
+		return migrateTable(s, v)
+	case rel.Index:
+		return migrateIndex(s, v)
+	}
+
+	return fmt.Errorf("%w: %T migration", ErrNotSupported, migration)
+}
+
+func migrateTable(s *snapshot, tbl rel.Table) error {
+	_, exists := s.table(tbl.Name)
+
+	switch tbl.Op {
+	case rel.SchemaCreate:
+		if exists {
+			if tbl.Optional {
+				return nil
+			}
+
+			return fmt.Errorf("memory: table %s already exists", tbl.Name)
+		}
+
+		return defineTable(tbl.Name, s.writable(tbl.Name), tbl.Definitions)
+	case rel.SchemaAlter:
+		if !exists {
+			return fmt.Errorf("memory: table %s does not exist", tbl.Name)
+		}
+
+		return defineTable(tbl.Name, s.writable(tbl.Name), tbl.Definitions)
+	case rel.SchemaRename:
+		if !exists {
+			return fmt.Errorf("memory: table %s does not exist", tbl.Name)
+		}
+
+		if _, ok := s.table(tbl.Rename); ok {
+			return fmt.Errorf("memory: table %s already exists", tbl.Rename)
+		}
+
+		*s.writable(tbl.Rename) = *s.writable(tbl.Name)
+		s.drop(tbl.Name)
+	case rel.SchemaDrop:
+		if !exists {
+			if tbl.Optional {
+				return nil
+			}
+
+			return fmt.Errorf("memory: table %s does not exist", tbl.Name)
+		}
+
+		s.drop(tbl.Name)
+	}
+
+	return nil
+}
+
+func defineTable(name string, t *table, definitions []rel.TableDefinition) error {
+	for _, definition := range definitions {
+		switch def := definition.(type) {
+		case rel.Column:
+			defineColumn(name, t, def)
+		case rel.Key:
+			defineKey(name, t, def)
+		default:
+			return fmt.Errorf("%w: %T table definition", ErrNotSupported, definition)
+		}
+	}
+
+	return nil
+}
+
+func defineColumn(name string, t *table, column rel.Column) {
+	switch column.Op {
+	case rel.SchemaCreate:
+		t.addColumn(column.Name)
+
+		if column.Default != nil {
+			value := normalize(column.Default)
+			t.defaults[column.Name] = value
+
+			for i := range t.rows {
+				if _, ok := t.rows[i][column.Name]; !ok {
+					r := t.rows[i].clone()
+					r[column.Name] = value
+					t.rows[i] = r
+				}
+			}
+		}
+
+		if column.Primary {
+			if len(t.primary.columns) == 0 {
+				t.primary = constraint{name: name + "_pkey"}
+			}
+
+			t.primary.columns = append(t.primary.columns[:len(t.primary.columns):len(t.primary.columns)], column.Name)
+		}
+
+		if column.Unique {
+			t.uniques = append(t.uniques, constraint{name: name + "_" + column.Name + "_key", columns: []string{column.Name}})
+		}
+	case rel.SchemaRename:
+		for i := range t.columns {
+			if t.columns[i] == column.Name {
+				t.columns[i] = column.Rename
+			}
+		}
+
+		if value, ok := t.defaults[column.Name]; ok {
+			t.defaults[column.Rename] = value
+			delete(t.defaults, column.Name)
+		}
+
+		for i := range t.rows {
+			if value, ok := t.rows[i][column.Name]; ok {
+				r := t.rows[i].clone()
+				r[column.Rename] = value
+				delete(r, column.Name)
+				t.rows[i] = r
+			}
+		}
+
+		t.primary.columns = renameColumns(t.primary.columns, column.Name, column.Rename)
+		for i := range t.uniques {
+			t.uniques[i].columns = renameColumns(t.uniques[i].columns, column.Name, column.Rename)
+		}
+	case rel.SchemaDrop:
+		t.removeColumn(column.Name)
+		delete(t.defaults, column.Name)
+
+		for i := range t.rows {
+			if _, ok := t.rows[i][column.Name]; ok {
+				r := t.rows[i].clone()
+				delete(r, column.Name)
+				t.rows[i] = r
+			}
+		}
+
+		if hasColumn(t.primary.columns, column.Name) {
+			t.primary = constraint{}
+		}
+
+		uniques := t.uniques[:0:0]
+		for _, unique := range t.uniques {
+			if !hasColumn(unique.columns, column.Name) {
+				uniques = append(uniques, unique)
+			}
+		}
+
+		t.uniques = uniques
+	}
+}
+
+func defineKey(name string, t *table, key rel.Key) {
+	switch key.Type {
+	case rel.PrimaryKey:
+		switch key.Op {
+		case rel.SchemaCreate:
+			t.primary = constraint{name: keyName(key.Name, name+"_pkey"), columns: key.Columns}
+		case rel.SchemaDrop:
+			t.primary = constraint{}
+		}
+	case rel.UniqueKey:
+		switch key.Op {
+		case rel.SchemaCreate:
+			t.uniques = append(t.uniques, constraint{
+				name:    keyName(key.Name, name+"_"+strings.Join(key.Columns, "_")+"_key"),
+				columns: key.Columns,
+			})
+		case rel.SchemaRename:
+			renameConstraint(t, key.Name, key.Rename)
+		case rel.SchemaDrop:
+			dropConstraint(t, key.Name)
+		}
+	}
+
+	// foreign keys are not enforced.
+}
+
+func migrateIndex(s *snapshot, index rel.Index) error {
+	// only unique index affects the behavior of memory adapter.
+	if _, exists := s.table(index.Table); !exists {
+		return fmt.Errorf("memory: table %s does not exist", index.Table)
+	}
+
+	switch index.Op {
+	case rel.SchemaCreate:
+		if index.Unique {
+			t := s.writable(index.Table)
+			t.uniques = append(t.uniques, constraint{name: index.Name, columns: index.Columns})
+		}
+	case rel.SchemaDrop:
+		dropConstraint(s.writable(index.Table), index.Name)
+	}
+
+	return nil
+}
+
+func renameConstraint(t *table, name string, rename string) {
+	for i := range t.uniques {
+		if t.uniques[i].name == name {
+			t.uniques[i].name = rename
+		}
+	}
+}
+
+func dropConstraint(t *table, name string) {
+	uniques := t.uniques[:0:0]
+	for _, unique := range t.uniques {
+		if unique.name != name {
+			uniques = append(uniques, unique)
+		}
+	}
+
+	t.uniques = uniques
+}
+
+func keyName(name string, fallback string) string {
+	if name != "" {
+		return name
+	}
+
+	return fallback
+}
+
+func renameColumns(columns []string, name string, rename string) []string {
+	result := make([]string, len(columns))
+	for i := range columns {
+		if columns[i] == name {
+			result[i] = rename
+		} else {
+			result[i] = columns[i]
+		}
+	}
+
+	return result
+}
+
+func hasColumn(columns []string, name string) bool {
+	for i := range columns {
+		if columns[i] == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/go-rel/rel"
+)
+
+// conflict returns index of existing row that violates primary or unique key when r is stored at given index.
+// Index is -1 when r is a new row, nil values never conflict.
+func (t *table) conflict(r row, index int) (int, rel.ConstraintType, constraint) {
+	if i := t.find(r, index, t.primary.columns); i >= 0 {
+		return i, rel.PrimaryKeyConstraint, t.primary
+	}
+
+	for _, unique := range t.uniques {
+		if i := t.find(r, index, unique.columns); i >= 0 {
+			return i, rel.UniqueConstraint, unique
+		}
+	}
+
+	return -1, 0, constraint{}
+}
+
+func (t *table) find(r row, index int, columns []string) int {
+	if len(columns) == 0 {
+		return -1
+	}
+
+	values := make([]any, len(columns))
+	for i := range columns {
+		if values[i] = r[columns[i]]; values[i] == nil {
+			return -1
+		}
+	}
+
+	k := key(values...)
+	for i := range t.rows {
+		if i == index {
+			continue
+		}
+
+		for j := range columns {
+			values[j] = t.rows[i][columns[j]]
+		}
+
+		if key(values...) == k {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// check returns constraint error if r can't be stored at given index.
+func (t *table) check(r row, index int) error {
+	if i, typ, c := t.conflict(r, index); i >= 0 {
+		return rel.ConstraintError{
+			Key:  c.name,
+			Type: typ,
+			Err:  fmt.Errorf("memory: duplicate value violates %s", c.name),
+		}
+	}
+
+	return nil
+}
+
+func (e executor) insert(query rel.Query, primaryField string, bulkMutates []map[string]rel.Mutate, onConflict rel.OnConflict) ([]any, error) {
+	if onConflict.Fragment != "" {
+		return nil, fmt.Errorf("%w: on conflict fragment", ErrNotSupported)
+	}
+
+	var (
+		name = e.source(query.Table).name
+		t    = e.snapshot.writable(name)
+	)
+
+	if primaryField != "" {
+		t.addColumn(primaryField)
+		if len(t.primary.columns) == 0 {
+			t.primary = constraint{name: name + "_pkey", columns: []string{primaryField}}
+		}
+	}
+
+	ids := make([]any, len(bulkMutates))
+	for i, mutates := range bulkMutates {
+		r := make(row, len(t.columns)+len(mutates))
+		for field, value := range t.defaults {
+			r[field] = value
+		}
+
+		for field, mut := range mutates {
+			if mut.Type != rel.ChangeSetOp {
+				return nil, fmt.Errorf("%w: %s mutation on insert", ErrNotSupported, field)
+			}
+
+			r[field] = normalize(mut.Value)
+			t.addColumn(field)
+		}
+
+		if primaryField != "" {
+			switch id := r[primaryField].(type) {
+			case nil:
+				t.sequence++
+				r[primaryField] = t.sequence
+			case int64:
+				if id == 0 {
+					t.sequence++
+					r[primaryField] = t.sequence
+				} else if id > t.sequence {
+					t.sequence = id
+				}
+			}
+		}
+
+		if j, _, c := t.conflict(r, -1); j >= 0 && (onConflict.Ignore || onConflict.Replace) && conflictKeys(onConflict.Keys, c) {
+			if onConflict.Replace {
+				replaced := t.rows[j].clone()
+				for field := range mutates {
+					replaced[field] = r[field]
+				}
+
+				if err := t.check(replaced, j); err != nil {
+					return nil, err
+				}
+
+				t.rows[j] = replaced
+			}
+
+			ids[i] = t.rows[j][primaryField]
+			continue
+		}
+
+		if err := t.check(r, -1); err != nil {
+			return nil, err
+		}
+
+		t.rows = append(t.rows, r)
+		ids[i] = r[primaryField]
+	}
+
+	return ids, nil
+}
+
+// conflictKeys returns true if on conflict keys targets the violated constraint, empty keys targets any constraint.
+func conflictKeys(keys []string, c constraint) bool {
+	if len(keys) == 0 {
+		return true
+	}
+
+	if len(keys) != len(c.columns) {
+		return false
+	}
+
+	for i := range keys {
+		found := false
+		for j := range c.columns {
+			if keys[i] == c.columns[j] {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches returns indexes of rows in the table that matches where clause of the query.
+func (e executor) matches(t *table, query rel.Query) ([]int, error) {
+	if len(query.JoinQuery) > 0 {
+		return nil, fmt.Errorf("%w: join on update or delete", ErrNotSupported)
+	}
+
+	var (
+		alias   = e.source(query.Table).alias
+		indexes []int
+	)
+
+	for i := range t.rows {
+		if ok, err := e.match(query.WhereQuery, newRecord(alias, t.rows[i])); err != nil {
+			return nil, err
+		} else if ok {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes, nil
+}
+
+func (e executor) update(query rel.Query, mutates map[string]rel.Mutate) (int, error) {
+	var (
+		name = e.source(query.Table).name
+	)
+
+	t, ok := e.snapshot.table(name)
+	if !ok {
+		return 0, nil
+	}
+
+	indexes, err := e.matches(t, query)
+	if err != nil || len(indexes) == 0 {
+		return 0, err
+	}
+
+	t = e.snapshot.writable(name)
+	for _, i := range indexes {
+		r := t.rows[i].clone()
+		for field, mut := range mutates {
+			switch mut.Type {
+			case rel.ChangeSetOp:
+				r[field] = normalize(mut.Value)
+				t.addColumn(field)
+			case rel.ChangeIncOp:
+				if r[field], err = add(r[field], normalize(mut.Value)); err != nil {
+					return 0, err
+				}
+			case rel.ChangeJSONSetOp:
+				args, _ := mut.Value.([]any)
+				if len(args) != 2 {
+					return 0, fmt.Errorf("memory: json set on %s requires path and value", field)
+				}
+
+				path, _ := args[0].(string)
+				if r[field], err = mutateJSON(r[field], path, true, args[1]); err != nil {
+					return 0, err
+				}
+
+				t.addColumn(field)
+			case rel.ChangeJSONRemoveOp:
+				path, _ := mut.Value.(string)
+				if r[field], err = mutateJSON(r[field], path, false, nil); err != nil {
+					return 0, err
+				}
+			default:
+				return 0, fmt.Errorf("%w: %s mutation on update", ErrNotSupported, field)
+			}
+		}
+
+		if err := t.check(r, i); err != nil {
+			return 0, err
+		}
+
+		t.rows[i] = r
+	}
+
+	return len(indexes), nil
+}
+
+func (e executor) delete(query rel.Query) (int, error) {
+	var (
+		name = e.source(query.Table).name
+	)
+
+	t, ok := e.snapshot.table(name)
+	if !ok {
+		return 0, nil
+	}
+
+	indexes, err := e.matches(t, query)
+	if err != nil || len(indexes) == 0 {
+		return 0, err
+	}
+
+	var (
+		rows = make([]row, 0, len(t.rows)-len(indexes))
+		next = 0
+	)
+
+	for i := range t.rows {
+		if next < len(indexes) && indexes[next] == i {
+			next++
+			continue
+		}
+
+		rows = append(rows, t.rows[i])
+	}
+
+	e.snapshot.writable(name).rows = rows
+	return len(indexes), nil
+}
@@ -0,0 +1,826 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-rel/rel"
+)
+
+var aggregateExpr = regexp.MustCompile(`(?i)^\s*(count|sum|avg|min|max)\s*\(\s*(distinct\s+)?([^)]*?)\s*\)\s*$`)
+
+// valuer provides value of a field, it's either a single record or a group of records.
+type valuer interface {
+	value(field string) any
+}
+
+// record of a row, each column is accessible using column name and qualified column name (table.column).
+type record map[string]any
+
+func (r record) value(field string) any {
+	return r[field]
+}
+
+func newRecord(alias string, r row) record {
+	rec := make(record, len(r)*2)
+	for k, v := range r {
+		rec[k] = v
+		rec[alias+"."+k] = v
+	}
+
+	return rec
+}
+
+type group []record
+
+func (g group) value(field string) any {
+	if fn, arg, distinct, ok := parseAggregate(field); ok {
+		return aggregate(fn, arg, distinct, g)
+	}
+
+	if len(g) > 0 {
+		return g[0][field]
+	}
+
+	return nil
+}
+
+type source struct {
+	name  string
+	alias string
+	table *table
+}
+
+func (s source) columns() []string {
+	if s.table == nil {
+		return nil
+	}
+
+	return s.table.columns
+}
+
+// scope resolves field of the current record first, then field of the outer query for correlated sub query.
+type scope struct {
+	current valuer
+	outer   valuer
+}
+
+func (s scope) value(field string) any {
+	if rec, ok := s.current.(record); ok {
+		if v, ok := rec[field]; ok {
+			return v
+		}
+	} else if v := s.current.value(field); v != nil {
+		return v
+	}
+
+	if s.outer != nil {
+		return s.outer.value(field)
+	}
+
+	return nil
+}
+
+// executor evaluates query against tables in a snapshot.
+type executor struct {
+	snapshot *snapshot
+	ctes     map[string]*table
+	outer    valuer
+}
+
+// correlate returns executor for sub query, fields of v can be referenced by the sub query.
+func (e executor) correlate(v valuer) executor {
+	e.outer = scope{current: v, outer: e.outer}
+	return e
+}
+
+// field returns value of field from the current record, or from the outer query when not found.
+func (e executor) field(v valuer, name string) any {
+	return scope{current: v, outer: e.outer}.value(name)
+}
+
+func (e executor) source(name string) source {
+	var (
+		alias = name
+	)
+
+	if n, a, ok := splitAlias(name); ok {
+		name, alias = n, a
+	}
+
+	t, ok := e.ctes[name]
+	if !ok {
+		t, _ = e.snapshot.table(name)
+	}
+
+	return source{name: name, alias: alias, table: t}
+}
+
+// with evaluates common table expressions, and returns executor that resolves them as tables.
+func (e executor) with(wqs []rel.WithQuery) (executor, error) {
+	var (
+		ctes   = make(map[string]*table, len(e.ctes)+len(wqs))
+		scoped = executor{snapshot: e.snapshot, ctes: ctes, outer: e.outer}
+	)
+
+	for k, v := range e.ctes {
+		ctes[k] = v
+	}
+
+	for _, wq := range wqs {
+		cur, err := scoped.query(wq.Query)
+		if err != nil {
+			return e, err
+		}
+
+		var (
+			result  = &table{columns: cur.fields}
+			working = cur.records
+		)
+
+		for {
+			for _, values := range working {
+				r := make(row, len(result.columns))
+				for i := range result.columns {
+					if i < len(values) {
+						r[result.columns[i]] = values[i]
+					}
+				}
+
+				result.rows = append(result.rows, r)
+			}
+
+			if !wq.Recursive || len(working) == 0 {
+				break
+			}
+
+			// recursive member only sees rows produced by the previous iteration.
+			ctes[wq.Name] = &table{columns: result.columns, rows: result.rows[len(result.rows)-len(working):]}
+			if cur, err = scoped.query(wq.RecursiveQuery); err != nil {
+				return e, err
+			}
+
+			working = cur.records
+		}
+
+		ctes[wq.Name] = result
+	}
+
+	return scoped, nil
+}
+
+// records returns all records that matches table, join and where clause of the query.
+func (e executor) records(query rel.Query) ([]record, []source, error) {
+	if query.SQLQuery.Statement != "" {
+		return nil, nil, fmt.Errorf("%w: raw sql query", ErrNotSupported)
+	}
+
+	if len(query.WithQuery) > 0 {
+		var err error
+		if e, err = e.with(query.WithQuery); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		main    = e.source(query.Table)
+		sources = []source{main}
+		records []record
+	)
+
+	if main.table != nil {
+		records = make([]record, 0, len(main.table.rows))
+		for _, r := range main.table.rows {
+			records = append(records, newRecord(main.alias, r))
+		}
+	}
+
+	for _, jq := range query.JoinQuery {
+		var (
+			err    error
+			joined source
+		)
+
+		if records, joined, err = e.join(records, jq); err != nil {
+			return nil, nil, err
+		}
+
+		sources = append(sources, joined)
+	}
+
+	if query.WhereQuery.None() {
+		return records, sources, nil
+	}
+
+	var (
+		filtered = records[:0:0]
+	)
+
+	for _, rec := range records {
+		if ok, err := e.match(query.WhereQuery, rec); err != nil {
+			return nil, nil, err
+		} else if ok {
+			filtered = append(filtered, rec)
+		}
+	}
+
+	return filtered, sources, nil
+}
+
+func (e executor) join(records []record, jq rel.JoinQuery) ([]record, source, error) {
+	var (
+		left   bool
+		mode   = strings.ToUpper(jq.Mode)
+		joined = e.source(jq.Table)
+		result = records[:0:0]
+	)
+
+	if jq.Arguments != nil {
+		return nil, joined, fmt.Errorf("%w: join fragment", ErrNotSupported)
+	}
+
+	switch {
+	case strings.Contains(mode, "LEFT"):
+		left = true
+	case strings.Contains(mode, "RIGHT"), strings.Contains(mode, "FULL"), strings.Contains(mode, "CROSS"):
+		return nil, joined, fmt.Errorf("%w: %s", ErrNotSupported, jq.Mode)
+	}
+
+	for _, rec := range records {
+		matched := false
+
+		if joined.table != nil {
+			for _, r := range joined.table.rows {
+				merged := make(record, len(rec)+len(r))
+				for k, v := range rec {
+					merged[k] = v
+				}
+
+				for k, v := range r {
+					merged[joined.alias+"."+k] = v
+				}
+
+				if jq.From != "" && jq.To != "" && !equal(merged[jq.From], merged[jq.To]) {
+					continue
+				}
+
+				if ok, err := e.match(jq.Filter, merged); err != nil {
+					return nil, joined, err
+				} else if !ok {
+					continue
+				}
+
+				matched = true
+				result = append(result, merged)
+			}
+		}
+
+		if !matched && left {
+			merged := make(record, len(rec))
+			for k, v := range rec {
+				merged[k] = v
+			}
+
+			for _, column := range joined.columns() {
+				merged[joined.alias+"."+column] = nil
+			}
+
+			result = append(result, merged)
+		}
+	}
+
+	return result, joined, nil
+}
+
+type selector struct {
+	name  string
+	field string
+	expr  rel.Expr
+}
+
+type item struct {
+	src    valuer
+	values []any
+	fields map[string]int
+}
+
+func (it item) value(field string) any {
+	if i, ok := it.fields[field]; ok {
+		return it.values[i]
+	}
+
+	return it.src.value(field)
+}
+
+// query evaluates the query and returns cursor of the result.
+func (e executor) query(query rel.Query) (*cursor, error) {
+	if len(query.WithQuery) > 0 {
+		var err error
+		if e, err = e.with(query.WithQuery); err != nil {
+			return nil, err
+		}
+
+		query.WithQuery = nil
+	}
+
+	records, sources, err := e.records(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		selectors = e.selectors(query.SelectQuery, sources)
+		valuers   = make([]valuer, 0, len(records))
+		fields    = make([]string, len(selectors))
+		index     = make(map[string]int, len(selectors))
+	)
+
+	for i := range selectors {
+		fields[i] = selectors[i].name
+		index[selectors[i].name] = i
+	}
+
+	if len(query.GroupQuery.Fields) > 0 || hasAggregate(selectors) {
+		groups, err := e.group(records, query.GroupQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range groups {
+			valuers = append(valuers, groups[i])
+		}
+	} else {
+		for i := range records {
+			valuers = append(valuers, records[i])
+		}
+	}
+
+	var (
+		items   = make([]item, 0, len(valuers))
+		windows []int
+	)
+
+	for i := range selectors {
+		if _, ok := selectors[i].expr.(rel.WindowExpr); ok {
+			windows = append(windows, i)
+		}
+	}
+
+	for _, v := range valuers {
+		values := make([]any, len(selectors))
+		for i := range selectors {
+			switch expr := selectors[i].expr.(type) {
+			case nil:
+				values[i] = v.value(selectors[i].field)
+			case rel.WindowExpr:
+				// evaluated after all rows are available.
+			default:
+				if values[i], err = e.eval(expr, v); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		items = append(items, item{src: v, values: values, fields: index})
+	}
+
+	for _, i := range windows {
+		if err := e.window(selectors[i].expr.(rel.WindowExpr), items, i); err != nil {
+			return nil, err
+		}
+	}
+
+	if query.SelectQuery.OnlyDistinct {
+		var (
+			distinct = items[:0]
+			seen     = make(map[string]struct{}, len(items))
+		)
+
+		for _, it := range items {
+			k := key(it.values...)
+			if _, exists := seen[k]; !exists {
+				seen[k] = struct{}{}
+				distinct = append(distinct, it)
+			}
+		}
+
+		items = distinct
+	}
+
+	for _, cq := range query.CombinationQuery {
+		if items, err = e.combine(items, fields, index, cq); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(query.SortQuery) > 0 {
+		if items, err = e.sort(items, query.SortQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	if pl := query.PartitionLimitQuery; pl.Limit > 0 {
+		var (
+			limited = items[:0]
+			counts  = make(map[string]int)
+		)
+
+		for _, it := range items {
+			k := key(it.value(pl.Field))
+			if counts[k] < pl.Limit {
+				counts[k]++
+				limited = append(limited, it)
+			}
+		}
+
+		items = limited
+	}
+
+	if offset := int(query.OffsetQuery); offset > 0 {
+		if offset > len(items) {
+			offset = len(items)
+		}
+
+		items = items[offset:]
+	}
+
+	if limit := int(query.LimitQuery); limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	cur := &cursor{
+		fields:  fields,
+		records: make([][]any, len(items)),
+	}
+
+	for i := range items {
+		cur.records[i] = items[i].values
+	}
+
+	return cur, nil
+}
+
+// sort items by sort queries, sort expression is evaluated against the item.
+func (e executor) sort(items []item, sorts []rel.SortQuery) ([]item, error) {
+	var (
+		order  = make([]int, len(items))
+		values = make([][]any, len(items))
+		err    error
+	)
+
+	for i := range items {
+		order[i] = i
+		values[i] = make([]any, len(sorts))
+		for j, sq := range sorts {
+			if sq.Expr == nil {
+				values[i][j] = items[i].value(sq.Field)
+			} else if values[i][j], err = e.eval(sq.Expr, items[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		for j, sq := range sorts {
+			c := less(values[order[a]][j], values[order[b]][j])
+			if c == 0 {
+				continue
+			}
+
+			return (c < 0) == sq.Asc()
+		}
+
+		return false
+	})
+
+	sorted := make([]item, len(items))
+	for i := range order {
+		sorted[i] = items[order[i]]
+	}
+
+	return sorted, nil
+}
+
+// combine result items with result of another query using set operation, rows are matched by position.
+func (e executor) combine(items []item, fields []string, index map[string]int, cq rel.CombinationQuery) ([]item, error) {
+	cur, err := e.query(cq.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		operator = strings.ToUpper(strings.Join(strings.Fields(cq.Operator), " "))
+		all      = strings.HasSuffix(operator, " ALL")
+		counts   = make(map[string]int, len(cur.records))
+		result   = items[:0:0]
+	)
+
+	if all {
+		operator = strings.TrimSuffix(operator, " ALL")
+	}
+
+	switch operator {
+	case "UNION":
+		result = append(result, items...)
+		for i := range cur.records {
+			values := make([]any, len(fields))
+			copy(values, cur.records[i])
+			result = append(result, item{src: record{}, values: values, fields: index})
+		}
+	case "INTERSECT", "EXCEPT":
+		for i := range cur.records {
+			counts[key(cur.records[i]...)]++
+		}
+
+		for _, it := range items {
+			k := key(it.values...)
+			if (counts[k] > 0) == (operator == "INTERSECT") {
+				result = append(result, it)
+			}
+
+			if all && counts[k] > 0 {
+				counts[k]--
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNotSupported, cq.Operator)
+	}
+
+	if all {
+		return result, nil
+	}
+
+	var (
+		distinct = result[:0]
+		seen     = make(map[string]struct{}, len(result))
+	)
+
+	for _, it := range result {
+		k := key(it.values...)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			distinct = append(distinct, it)
+		}
+	}
+
+	return distinct, nil
+}
+
+// selectors expands select fields and expressions into list of result column name and field or expression to be evaluated.
+func (e executor) selectors(sq rel.SelectQuery, sources []source) []selector {
+	var (
+		fields = sq.Fields
+	)
+
+	if len(fields) == 0 {
+		fields = []string{"*"}
+	}
+
+	var (
+		result = make([]selector, 0, len(fields))
+	)
+
+	for _, f := range fields {
+		var (
+			expr  = strings.TrimSpace(f)
+			alias string
+		)
+
+		if e, a, ok := splitAlias(expr); ok {
+			expr, alias = e, a
+		}
+
+		switch {
+		case expr == "*":
+			for _, column := range sources[0].columns() {
+				result = append(result, selector{name: column, field: column})
+			}
+		case strings.HasSuffix(expr, ".*"):
+			name := strings.TrimSuffix(expr, ".*")
+			for _, s := range sources {
+				if s.alias == name {
+					for _, column := range s.columns() {
+						result = append(result, selector{name: column, field: s.alias + "." + column})
+					}
+				}
+			}
+		default:
+			name := alias
+			if name == "" {
+				name = expr
+				if _, _, _, ok := parseAggregate(expr); !ok {
+					if i := strings.LastIndexByte(expr, '.'); i >= 0 {
+						name = expr[i+1:]
+					}
+				}
+			}
+
+			result = append(result, selector{name: name, field: expr})
+		}
+	}
+
+	for _, se := range sq.Expressions {
+		result = append(result, selector{name: se.Alias, expr: se.Expr})
+	}
+
+	return result
+}
+
+func (e executor) group(records []record, gq rel.GroupQuery) ([]group, error) {
+	var (
+		groups []group
+		index  = make(map[string]int)
+	)
+
+	if len(gq.Fields) == 0 {
+		groups = []group{records}
+	} else {
+		for _, rec := range records {
+			values := make([]any, len(gq.Fields))
+			for i := range gq.Fields {
+				values[i] = rec[gq.Fields[i]]
+			}
+
+			k := key(values...)
+			if i, ok := index[k]; ok {
+				groups[i] = append(groups[i], rec)
+			} else {
+				index[k] = len(groups)
+				groups = append(groups, group{rec})
+			}
+		}
+	}
+
+	if gq.Filter.None() {
+		return groups, nil
+	}
+
+	var (
+		filtered = groups[:0:0]
+	)
+
+	for _, g := range groups {
+		if ok, err := e.match(gq.Filter, g); err != nil {
+			return nil, err
+		} else if ok {
+			filtered = append(filtered, g)
+		}
+	}
+
+	return filtered, nil
+}
+
+// aggregateRecords returns records to be aggregated, combined query is evaluated as a whole.
+func (e executor) aggregateRecords(query rel.Query) ([]record, error) {
+	if len(query.CombinationQuery) == 0 {
+		records, _, err := e.records(query)
+		return records, err
+	}
+
+	cur, err := e.query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]record, len(cur.records))
+	for i := range cur.records {
+		records[i] = make(record, len(cur.fields))
+		for j := range cur.fields {
+			records[i][cur.fields[j]] = cur.records[i][j]
+		}
+	}
+
+	return records, nil
+}
+
+// column evaluates query and returns values of the first column, used by sub query.
+func (e executor) column(query rel.Query) ([]any, error) {
+	cur, err := e.query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]any, 0, len(cur.records))
+	for i := range cur.records {
+		if len(cur.records[i]) > 0 {
+			values = append(values, cur.records[i][0])
+		}
+	}
+
+	return values, nil
+}
+
+func hasAggregate(selectors []selector) bool {
+	for i := range selectors {
+		if fe, ok := selectors[i].expr.(rel.FuncExpr); ok && isAggregate(fe.Name) {
+			return true
+		}
+
+		if _, _, _, ok := parseAggregate(selectors[i].field); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseAggregate(expr string) (string, string, bool, bool) {
+	if !strings.HasSuffix(expr, ")") {
+		return "", "", false, false
+	}
+
+	match := aggregateExpr.FindStringSubmatch(expr)
+	if match == nil {
+		return "", "", false, false
+	}
+
+	return strings.ToLower(match[1]), match[3], match[2] != "", true
+}
+
+func aggregate(fn string, field string, distinct bool, records []record) any {
+	var (
+		values = make([]any, 0, len(records))
+		seen   = make(map[string]struct{})
+	)
+
+	for _, rec := range records {
+		if field == "*" {
+			values = append(values, int64(1))
+			continue
+		}
+
+		v := rec[field]
+		if v == nil {
+			continue
+		}
+
+		if distinct {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+
+			seen[k] = struct{}{}
+		}
+
+		values = append(values, v)
+	}
+
+	switch fn {
+	case "count":
+		return int64(len(values))
+	case "sum", "avg":
+		if len(values) == 0 {
+			return nil
+		}
+
+		var (
+			isum    int64
+			fsum    float64
+			isFloat bool
+		)
+
+		for _, v := range values {
+			switch n := v.(type) {
+			case int64:
+				isum += n
+			case float64:
+				fsum += n
+				isFloat = true
+			}
+		}
+
+		if fn == "avg" {
+			return (float64(isum) + fsum) / float64(len(values))
+		}
+
+		if isFloat {
+			return float64(isum) + fsum
+		}
+
+		return isum
+	case "min", "max":
+		var result any
+		for i, v := range values {
+			c := less(v, result)
+			if i == 0 || (fn == "min" && c < 0) || (fn == "max" && c > 0) {
+				result = v
+			}
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+// splitAlias splits "expr as alias" expression.
+func splitAlias(expr string) (string, string, bool) {
+	if i := strings.LastIndex(strings.ToLower(expr), " as "); i >= 0 {
+		return strings.TrimSpace(expr[:i]), strings.TrimSpace(expr[i+4:]), true
+	}
+
+	return expr, "", false
+}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-rel/rel"
+)
+
+type row map[string]any
+
+// clone returns shallow copy of the row, rows are never mutated after it's stored in table.
+func (r row) clone() row {
+	c := make(row, len(r))
+	for k, v := range r {
+		c[k] = v
+	}
+
+	return c
+}
+
+// constraint is a primary or unique key of a table.
+type constraint struct {
+	name    string
+	columns []string
+}
+
+type table struct {
+	columns  []string
+	defaults map[string]any
+	rows     []row
+	sequence int64
+	primary  constraint
+	uniques  []constraint
+}
+
+func (t *table) clone() *table {
+	c := &table{
+		columns:  append([]string(nil), t.columns...),
+		defaults: make(map[string]any, len(t.defaults)),
+		rows:     append([]row(nil), t.rows...),
+		sequence: t.sequence,
+		primary:  t.primary,
+		uniques:  append([]constraint(nil), t.uniques...),
+	}
+
+	for k, v := range t.defaults {
+		c.defaults[k] = v
+	}
+
+	return c
+}
+
+func (t *table) addColumn(name string) {
+	for i := range t.columns {
+		if t.columns[i] == name {
+			return
+		}
+	}
+
+	t.columns = append(t.columns, name)
+}
+
+func (t *table) removeColumn(name string) {
+	for i := range t.columns {
+		if t.columns[i] == name {
+			t.columns = append(t.columns[:i:i], t.columns[i+1:]...)
+			return
+		}
+	}
+}
+
+// snapshot is a copy-on-write view of tables.
+// Tables are shared between snapshots until it's modified, and cloned on the first write.
+type snapshot struct {
+	tables  map[string]*table
+	base    map[string]*table
+	owned   map[string]bool
+	written map[string]bool
+}
+
+func (s *snapshot) table(name string) (*table, bool) {
+	t, ok := s.tables[name]
+	return t, ok
+}
+
+// writable returns table that is safe to be modified by this snapshot, table is created when not exists.
+func (s *snapshot) writable(name string) *table {
+	t, ok := s.tables[name]
+	if !ok {
+		t = &table{defaults: make(map[string]any)}
+		s.tables[name] = t
+		s.owned[name] = true
+	} else if !s.owned[name] {
+		t = t.clone()
+		s.tables[name] = t
+		s.owned[name] = true
+	}
+
+	s.written[name] = true
+	return t
+}
+
+func (s *snapshot) drop(name string) {
+	delete(s.tables, name)
+	delete(s.owned, name)
+	s.written[name] = true
+}
+
+// save current state, so it can be restored later.
+// tables owned by this snapshot are no longer owned, so it will be cloned on the next write.
+func (s *snapshot) save() state {
+	s.owned = make(map[string]bool)
+
+	return state{
+		tables:  copyTables(s.tables),
+		written: copyFlags(s.written),
+	}
+}
+
+func (s *snapshot) restore(st state) {
+	s.tables = copyTables(st.tables)
+	s.written = copyFlags(st.written)
+	s.owned = make(map[string]bool)
+}
+
+type state struct {
+	tables  map[string]*table
+	written map[string]bool
+}
+
+type savepoint struct {
+	name  string
+	state state
+}
+
+func newSnapshot(tables map[string]*table) *snapshot {
+	return &snapshot{
+		tables:  copyTables(tables),
+		base:    copyTables(tables),
+		owned:   make(map[string]bool),
+		written: make(map[string]bool),
+	}
+}
+
+// store holds committed tables shared by adapter and its transactions.
+type store struct {
+	lock   sync.Mutex
+	tables map[string]*table
+}
+
+// snapshot of committed tables, caller must hold the lock.
+func (st *store) snapshot() *snapshot {
+	return newSnapshot(st.tables)
+}
+
+// commit tables modified by snapshot, caller must hold the lock.
+// Committed table is never modified in place, so table that is no longer the same table the snapshot is based on
+// was written by another transaction after the snapshot is taken, and the commit fails with serialization error.
+func (st *store) commit(s *snapshot) error {
+	for name := range s.written {
+		if st.tables[name] != s.base[name] {
+			return rel.TransactionError{
+				Type: rel.SerializationFailure,
+				Err:  fmt.Errorf("memory: table %s is modified by concurrent transaction", name),
+			}
+		}
+	}
+
+	for name := range s.written {
+		if t, ok := s.tables[name]; ok {
+			st.tables[name] = t
+		} else {
+			delete(st.tables, name)
+		}
+	}
+
+	return nil
+}
+
+func copyTables(tables map[string]*table) map[string]*table {
+	c := make(map[string]*table, len(tables))
+	for k, v := range tables {
+		c[k] = v
+	}
+
+	return c
+}
+
+func copyFlags(flags map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(flags))
+	for k, v := range flags {
+		c[k] = v
+	}
+
+	return c
+}
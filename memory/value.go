@@ -0,0 +1,257 @@
+package memory
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+// normalize value into one of driver.Value types, so it can be compared consistently.
+// Values that can't be converted are stored as is.
+func normalize(value any) any {
+	if v, err := driver.DefaultParameterConverter.ConvertValue(value); err == nil {
+		return v
+	}
+
+	return value
+}
+
+// compare two normalized values, second return value is false if values are not comparable.
+func compare(a any, b any) (int, bool) {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, y), true
+		case float64:
+			return compareOrdered(float64(x), y), true
+		}
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return compareOrdered(x, float64(y)), true
+		case float64:
+			return compareOrdered(x, y), true
+		}
+	case string:
+		switch y := b.(type) {
+		case string:
+			return strings.Compare(x, y), true
+		case []byte:
+			return strings.Compare(x, string(y)), true
+		}
+	case []byte:
+		switch y := b.(type) {
+		case []byte:
+			return bytes.Compare(x, y), true
+		case string:
+			return strings.Compare(string(x), y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareOrdered(boolInt(x), boolInt(y)), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	}
+
+	if a != nil && b != nil && reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b {
+		return 0, true
+	}
+
+	return 0, false
+}
+
+type ordered interface {
+	~int | ~int64 | ~float64
+}
+
+func compareOrdered[T ordered](x T, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// equal returns true if both values are not nil and equal.
+func equal(a any, b any) bool {
+	c, ok := compare(a, b)
+	return ok && c == 0
+}
+
+// less is used for sorting, nil is always lesser than other values.
+func less(a any, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if c, ok := compare(a, b); ok {
+		return c
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// key returns string representation of values, used for grouping and distinct.
+func key(values ...any) string {
+	var builder strings.Builder
+	for i := range values {
+		if i > 0 {
+			builder.WriteByte(0)
+		}
+
+		switch v := values[i].(type) {
+		case nil:
+			builder.WriteString("<nil>")
+		case time.Time:
+			builder.WriteString(v.UTC().Format(time.RFC3339Nano))
+		case []byte:
+			builder.WriteString(string(v))
+		default:
+			fmt.Fprintf(&builder, "%T:%v", v, v)
+		}
+	}
+
+	return builder.String()
+}
+
+var likeReplacer = strings.NewReplacer("%", ".*", "_", ".")
+
+func like(value any, pattern string) bool {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return false
+	}
+
+	expr := "^" + likeReplacer.Replace(regexp.QuoteMeta(pattern)) + "$"
+	matched, _ := regexp.MatchString(expr, str)
+	return matched
+}
+
+// lower returns lower cased string value, non string value is returned as is.
+func lower(value any) any {
+	switch v := value.(type) {
+	case string:
+		return strings.ToLower(v)
+	case []byte:
+		return strings.ToLower(string(v))
+	}
+
+	return value
+}
+
+func matchRegexp(value any, pattern string) (bool, error) {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return false, nil
+	}
+
+	return regexp.MatchString(pattern, str)
+}
+
+// elements returns normalized elements of slice or array value, byte slice is not treated as slice.
+func elements(value any) ([]any, bool) {
+	if _, ok := value.([]byte); ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = normalize(rv.Index(i).Interface())
+	}
+
+	return values, true
+}
+
+func add(value any, inc any) (any, error) {
+	switch x := value.(type) {
+	case nil:
+		return nil, nil
+	case int64:
+		switch y := inc.(type) {
+		case int64:
+			return x + y, nil
+		case float64:
+			return float64(x) + y, nil
+		}
+	case float64:
+		switch y := inc.(type) {
+		case int64:
+			return x + float64(y), nil
+		case float64:
+			return x + y, nil
+		}
+	}
+
+	return nil, fmt.Errorf("memory: cannot increment %v by %v", value, inc)
+}
+
+// scan value to destination, destination is a scanner or a pointer returned by rel.Document.Scanners.
+func scan(dest any, value any) error {
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(value)
+	}
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("memory: destination must be a non nil pointer")
+	}
+
+	if ev := rv.Elem(); ev.Kind() == reflect.Ptr {
+		if value == nil {
+			ev.Set(reflect.Zero(ev.Type()))
+			return nil
+		}
+
+		nv := reflect.New(ev.Type().Elem())
+		if err := scan(nv.Interface(), value); err != nil {
+			return err
+		}
+
+		ev.Set(nv)
+		return nil
+	}
+
+	return rel.Nullable(dest).(sql.Scanner).Scan(value)
+}
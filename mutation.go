@@ -128,6 +128,10 @@ const (
 	ChangeIncOp
 	// ChangeFragmentOp operation.
 	ChangeFragmentOp
+	// ChangeJSONSetOp operation.
+	ChangeJSONSetOp
+	// ChangeJSONRemoveOp operation.
+	ChangeJSONRemoveOp
 )
 
 // Mutate stores mutation instruction.
@@ -146,6 +150,8 @@ func (m Mutate) Apply(doc *Document, mutation *Mutation) {
 		if !doc.SetValue(m.Field, m.Value) {
 			invalid = true
 		}
+
+		m.Value = wrapJSON(doc.meta, m.Field, m.Value)
 	case ChangeFragmentOp:
 		mutation.Reload = true
 	default:
@@ -175,6 +181,10 @@ func (m Mutate) String() string {
 		str = fmt.Sprintf("rel.IncBy(\"%s\", %s)", m.Field, fmtAny(m.Value))
 	case ChangeFragmentOp:
 		str = fmt.Sprintf("rel.SetFragment(\"%s\", %s)", m.Field, fmtAnys(m.Value.([]any)))
+	case ChangeJSONSetOp:
+		str = fmt.Sprintf("rel.JSONSet(\"%s\", %s)", m.Field, fmtAnys(m.Value.([]any)))
+	case ChangeJSONRemoveOp:
+		str = fmt.Sprintf("rel.JSONRemove(\"%s\", %s)", m.Field, fmtAny(m.Value))
 	}
 
 	return str
@@ -230,6 +240,27 @@ func SetFragment(raw string, args ...any) Mutate {
 // Setf is an alias for SetFragment
 var Setf = SetFragment
 
+// JSONSet create a mutate that sets value at the path inside json field, path uses json path syntax (eg: $.address.city).
+// Only one mutate is applied for each field, use Set to replace multiple keys at once.
+// Only available for Update.
+func JSONSet(field string, path string, value any) Mutate {
+	return Mutate{
+		Type:  ChangeJSONSetOp,
+		Field: field,
+		Value: []any{path, value},
+	}
+}
+
+// JSONRemove create a mutate that removes the path from json field.
+// Only available for Update.
+func JSONRemove(field string, path string) Mutate {
+	return Mutate{
+		Type:  ChangeJSONRemoveOp,
+		Field: field,
+		Value: path,
+	}
+}
+
 // Reload force reload after insert/update.
 // Default to false.
 type Reload bool
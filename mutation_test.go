@@ -51,6 +51,36 @@ func TestApply(t *testing.T) {
 	assert.Equal(t, 0, entity.Field5)
 }
 
+func TestApply_json(t *testing.T) {
+	var (
+		entity = struct {
+			ID       int
+			Settings map[string]any `db:"settings,json"`
+		}{}
+		doc      = NewDocument(&entity)
+		settings = map[string]any{"theme": "dark"}
+		mutators = []Mutator{
+			Set("settings", settings),
+		}
+		mutation = Mutation{
+			Cascade: true,
+			Mutates: map[string]Mutate{
+				"settings": Set("settings", jsonValue{value: settings}),
+			},
+		}
+	)
+
+	assert.Equal(t, mutation, Apply(doc, mutators...))
+	assert.Equal(t, settings, entity.Settings)
+
+	mutation = Apply(doc, JSONSet("settings", "$.theme", "light"))
+	assert.True(t, bool(mutation.Reload))
+	assert.Equal(t, Mutate{Type: ChangeJSONSetOp, Field: "settings", Value: []any{"$.theme", "light"}}, mutation.Mutates["settings"])
+	assert.Panics(t, func() {
+		Apply(doc, JSONRemove("unknown", "$.theme"))
+	})
+}
+
 func TestApply_Options(t *testing.T) {
 	var (
 		entity   = Testentity{}
@@ -162,5 +192,7 @@ func TestMutator_String(t *testing.T) {
 	assert.Equal(t, "rel.IncBy(\"count\", -1)", fmt.Sprint(Dec("count")))
 	assert.Equal(t, "rel.IncBy(\"count\", 1)", fmt.Sprint(Inc("count")))
 	assert.Equal(t, "rel.SetFragment(\"field = (?, ?, ?)\", 1, true, \"value\")", fmt.Sprint(SetFragment("field = (?, ?, ?)", 1, true, "value")))
+	assert.Equal(t, "rel.JSONSet(\"settings\", \"$.theme\", \"dark\")", fmt.Sprint(JSONSet("settings", "$.theme", "dark")))
+	assert.Equal(t, "rel.JSONRemove(\"settings\", \"$.theme\")", fmt.Sprint(JSONRemove("settings", "$.theme")))
 	assert.Equal(t, "rel.Cascade(true)", fmt.Sprint(Cascade(true)))
 }
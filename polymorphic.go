@@ -0,0 +1,45 @@
+package rel
+
+import (
+	"reflect"
+	"sync"
+)
+
+var polymorphicTypes sync.Map
+
+// RegisterPolymorphic registers entities as target of polymorphic belongs to association.
+// Type field of the association stores table name of the target, eg: `commentable_type` is "posts" for Post.
+func RegisterPolymorphic(entities ...any) {
+	for _, entity := range entities {
+		rt := reflect.TypeOf(entity)
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+
+		if rt.Kind() != reflect.Struct {
+			panic("rel: polymorphic target must be a struct or pointer to a struct")
+		}
+
+		polymorphicTypes.Store(getDocumentMeta(rt, false).Table(), rt)
+	}
+}
+
+// polymorphicType returns type registered for the table.
+func polymorphicType(table string) (reflect.Type, bool) {
+	if rt, ok := polymorphicTypes.Load(table); ok {
+		return rt.(reflect.Type), true
+	}
+
+	return nil, false
+}
+
+// polymorphicTarget assigns preloaded document into interface field of polymorphic belongs to association.
+type polymorphicTarget struct {
+	*Document
+	rv reflect.Value
+}
+
+// Append sets pointer to the document as the interface field value.
+func (pt polymorphicTarget) Append(doc *Document) {
+	pt.rv.Set(doc.rv.Addr())
+}
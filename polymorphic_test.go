@@ -0,0 +1,285 @@
+package rel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type Article struct {
+	ID       int
+	Title    string
+	Comments []Comment `polymorphic:"commentable" autosave:"true"`
+}
+
+type Video struct {
+	ID       int
+	Comments []Comment `polymorphic:"commentable"`
+}
+
+type Comment struct {
+	ID              int
+	CommentableID   int
+	CommentableType string
+	Body            string
+	Commentable     any `polymorphic:"commentable" autosave:"true"`
+}
+
+func init() {
+	RegisterPolymorphic(Article{}, &Video{})
+}
+
+func TestRegisterPolymorphic(t *testing.T) {
+	rt, ok := polymorphicType("videos")
+	assert.True(t, ok)
+	assert.Equal(t, "Video", rt.Name())
+
+	_, ok = polymorphicType("users")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		RegisterPolymorphic(1)
+	})
+}
+
+func TestAssociationMeta_polymorphic(t *testing.T) {
+	var (
+		comments    = NewDocument(&Article{}).Meta().Association("comments")
+		commentable = NewDocument(&Comment{}).Meta().Association("commentable")
+	)
+
+	assert.Equal(t, HasMany, int(comments.Type()))
+	assert.Equal(t, "id", comments.ReferenceField())
+	assert.Equal(t, "commentable_id", comments.ForeignField())
+	assert.Equal(t, "commentable_type", comments.PolymorphicField())
+	assert.Equal(t, "articles", comments.PolymorphicValue())
+	assert.True(t, comments.Autosave())
+
+	assert.Equal(t, BelongsTo, int(commentable.Type()))
+	assert.Equal(t, "commentable_id", commentable.ReferenceField())
+	assert.Equal(t, "id", commentable.ForeignField())
+	assert.Equal(t, "commentable_type", commentable.PolymorphicField())
+	assert.Equal(t, "", commentable.PolymorphicValue())
+	assert.Panics(t, func() { commentable.DocumentMeta() })
+}
+
+func TestDocumentMeta_polymorphic(t *testing.T) {
+	doc := NewDocument(&Comment{})
+	assert.Equal(t, []string{"id", "commentable_id", "commentable_type", "body"}, doc.Fields())
+	assert.Equal(t, []string{"commentable"}, doc.BelongsTo())
+}
+
+func TestAssociation_polymorphic(t *testing.T) {
+	var (
+		comment = Comment{CommentableID: 1, CommentableType: "articles"}
+		assoc   = NewDocument(&comment).Association("commentable")
+	)
+
+	assert.Equal(t, "articles", assoc.PolymorphicValue())
+	assert.True(t, assoc.IsZero())
+	assert.Nil(t, assoc.ForeignValue())
+
+	doc, loaded := assoc.Document()
+	assert.False(t, loaded)
+	assert.Equal(t, "articles", doc.Table())
+	assert.IsType(t, &Article{}, comment.Commentable)
+
+	comment.Commentable = &Video{ID: 2}
+	doc, loaded = assoc.Document()
+	assert.True(t, loaded)
+	assert.Equal(t, "videos", doc.Table())
+	assert.Equal(t, 2, assoc.ForeignValue())
+
+	comment = Comment{CommentableType: "users"}
+	doc, loaded = NewDocument(&comment).Association("commentable").Document()
+	assert.False(t, loaded)
+	assert.Nil(t, doc)
+}
+
+func TestRepository_Preload_polymorphicHasMany(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		article = Article{ID: 10}
+		cur     = &testCursor{}
+	)
+
+	adapter.On("Query", From("comments").Where(In("commentable_id", 10), Eq("commentable_type", "articles"))).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "commentable_id", "commentable_type"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(5, 10, "articles").Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(context.TODO(), &article, "comments"))
+	assert.Equal(t, []Comment{{ID: 5, CommentableID: 10, CommentableType: "articles"}}, article.Comments)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Preload_polymorphicBelongsTo(t *testing.T) {
+	var (
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		articles = &testCursor{}
+		videos   = &testCursor{}
+		comments = []Comment{
+			{ID: 1, CommentableID: 1, CommentableType: "articles"},
+			{ID: 2, CommentableID: 2, CommentableType: "videos"},
+			{ID: 3, CommentableID: 1, CommentableType: "articles"},
+			{ID: 4},
+		}
+	)
+
+	adapter.On("Query", From("articles").Where(In("id", 1))).Return(articles, nil).Once()
+	adapter.On("Query", From("videos").Where(In("id", 2))).Return(videos, nil).Once()
+
+	articles.On("Close").Return(nil).Once()
+	articles.On("Fields").Return([]string{"id", "title"}, nil).Once()
+	articles.On("Next").Return(true).Once()
+	articles.MockScan(1, "rel").Once()
+	articles.On("Next").Return(false).Once()
+
+	videos.On("Close").Return(nil).Once()
+	videos.On("Fields").Return([]string{"id"}, nil).Once()
+	videos.On("Next").Return(true).Once()
+	videos.MockScan(2).Once()
+	videos.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(context.TODO(), &comments, "commentable"))
+	assert.Equal(t, &Article{ID: 1, Title: "rel"}, comments[0].Commentable)
+	assert.Equal(t, &Video{ID: 2}, comments[1].Commentable)
+	assert.Equal(t, &Article{ID: 1, Title: "rel"}, comments[2].Commentable)
+	assert.Nil(t, comments[3].Commentable)
+
+	adapter.AssertExpectations(t)
+	articles.AssertExpectations(t)
+	videos.AssertExpectations(t)
+}
+
+func TestRepository_Preload_polymorphicNotRegistered(t *testing.T) {
+	var (
+		repo    = New(&testAdapter{})
+		comment = Comment{ID: 1, CommentableID: 1, CommentableType: "users"}
+	)
+
+	err := repo.Preload(context.TODO(), &comment, "commentable")
+	assert.Equal(t, UnknownPolymorphicTypeError{Type: "users"}, err)
+	assert.EqualError(t, err, "rel: polymorphic type (users) is not registered")
+	assert.Nil(t, comment.Commentable)
+}
+
+func TestRepository_Insert_polymorphicHasMany(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		article = Article{Title: "rel", Comments: []Comment{{Body: "nice"}}}
+		mutates = []map[string]Mutate{
+			{
+				"body":             Set("body", "nice"),
+				"commentable_id":   Set("commentable_id", 1),
+				"commentable_type": Set("commentable_type", "articles"),
+			},
+		}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Insert", From("articles"), map[string]Mutate{"title": Set("title", "rel")}, OnConflict{}).Return(1, nil).Once()
+	adapter.On("InsertAll", From("comments"), mock.Anything, mutates, OnConflict{}).Return([]any{2}, nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Insert(context.TODO(), &article))
+	assert.Equal(t, Article{
+		ID:       1,
+		Title:    "rel",
+		Comments: []Comment{{ID: 2, CommentableID: 1, CommentableType: "articles", Body: "nice"}},
+	}, article)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Update_polymorphicHasMany(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		article = Article{ID: 1, Title: "rel", Comments: []Comment{{Body: "nice"}}}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Update", From("articles").Where(Eq("id", 1)), "id", mock.Anything).Return(1, nil).Once()
+	adapter.On("Delete", From("comments").Where(Eq("commentable_id", 1), Eq("commentable_type", "articles"))).Return(1, nil).Once()
+	adapter.On("InsertAll", From("comments"), mock.Anything, mock.Anything, OnConflict{}).Return([]any{2}, nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Update(context.TODO(), &article))
+	assert.Equal(t, []Comment{{ID: 2, CommentableID: 1, CommentableType: "articles", Body: "nice"}}, article.Comments)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Insert_polymorphicBelongsTo(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		comment = Comment{Body: "nice", Commentable: &Article{Title: "rel"}}
+		mutates = map[string]Mutate{
+			"body":             Set("body", "nice"),
+			"commentable_id":   Set("commentable_id", 1),
+			"commentable_type": Set("commentable_type", "articles"),
+		}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Insert", From("articles"), map[string]Mutate{"title": Set("title", "rel")}, OnConflict{}).Return(1, nil).Once()
+	adapter.On("Insert", From("comments"), mutates, OnConflict{}).Return(2, nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Insert(context.TODO(), &comment))
+	assert.Equal(t, Comment{
+		ID:              2,
+		CommentableID:   1,
+		CommentableType: "articles",
+		Body:            "nice",
+		Commentable:     &Article{ID: 1, Title: "rel"},
+	}, comment)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestJoinAssoc_polymorphic(t *testing.T) {
+	var (
+		populated = Build("", NewJoinAssoc("comments")).Populate(NewDocument(&Article{}).Meta())
+	)
+
+	assert.Equal(t, JoinQuery{
+		Mode:   "JOIN",
+		Table:  "comments as comments",
+		To:     "comments.commentable_id",
+		From:   "articles.id",
+		Assoc:  "comments",
+		Filter: FilterQuery{}.AndEq("comments.commentable_type", "articles"),
+	}, populated.JoinQuery[0])
+
+	assert.Panics(t, func() {
+		Build("", NewJoinAssoc("commentable")).Populate(NewDocument(&Comment{}).Meta())
+	})
+}
+
+func TestHasAssoc_polymorphic(t *testing.T) {
+	var (
+		populated = Build("articles", HasAssoc("comments", Eq("comments.body", "nice"))).Populate(NewDocument(&Article{}).Meta())
+		sub       = From("comments as comments").
+				Where(Eq("comments.commentable_id", Field("articles.id")).AndEq("comments.commentable_type", "articles")).
+				Where(And(Eq("comments.body", "nice")))
+	)
+
+	assert.Equal(t, From("articles").Where(Exists(sub)).WhereQuery, populated.WhereQuery)
+
+	assert.Panics(t, func() {
+		Build("comments", HasAssoc("commentable")).Populate(NewDocument(&Comment{}).Meta())
+	})
+}
@@ -0,0 +1,215 @@
+// Package primaryreplica implements rel.Adapter that splits reads and writes between primary and replica databases.
+//
+// Reads are served by a replica chosen by Balancer, while writes, transactions, locking queries
+// and queries using rel.UsePrimary are served by the primary.
+package primaryreplica
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+// Adapter definition for primary replica database.
+type Adapter struct {
+	primary  rel.Adapter
+	replicas []rel.Adapter
+	balancer Balancer
+	window   time.Duration
+}
+
+var _ rel.Adapter = (*Adapter)(nil)
+
+// New primary replica adapter.
+// Reads are served by primary when no replica is given.
+func New(primary rel.Adapter, replicas ...rel.Adapter) *Adapter {
+	return &Adapter{
+		primary:  primary,
+		replicas: replicas,
+		balancer: RoundRobin(),
+	}
+}
+
+// Balance sets balancer used to choose replica, default is RoundRobin.
+func (a *Adapter) Balance(balancer Balancer) {
+	a.balancer = balancer
+}
+
+// ReadYourWrites routes reads to primary for the duration of window after a write made using the same session.
+// Session must be attached to context using WithSession.
+func (a *Adapter) ReadYourWrites(window time.Duration) {
+	a.window = window
+}
+
+// Primary adapter.
+func (a *Adapter) Primary() rel.Adapter {
+	return a.primary
+}
+
+// Replicas adapter.
+func (a *Adapter) Replicas() []rel.Adapter {
+	return a.replicas
+}
+
+// Name of database adapter.
+func (a *Adapter) Name() string {
+	return a.primary.Name()
+}
+
+// Close all database connections.
+func (a *Adapter) Close() error {
+	errs := make([]error, 0, len(a.replicas)+1)
+	errs = append(errs, a.primary.Close())
+	for i := range a.replicas {
+		errs = append(errs, a.replicas[i].Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Instrumentation set instrumenter for primary and replica adapters.
+func (a *Adapter) Instrumentation(instrumenter rel.Instrumenter) {
+	a.primary.Instrumentation(instrumenter)
+	for i := range a.replicas {
+		a.replicas[i].Instrumentation(instrumenter)
+	}
+}
+
+// Ping primary and replica databases.
+func (a *Adapter) Ping(ctx context.Context) error {
+	if err := a.primary.Ping(ctx); err != nil {
+		return err
+	}
+
+	for i := range a.replicas {
+		if err := a.replicas[i].Ping(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// read returns adapter that should serve the read query.
+func (a *Adapter) read(ctx context.Context, query rel.Query) rel.Adapter {
+	if len(a.replicas) == 0 || query.UsePrimaryDb || query.LockQuery != "" {
+		return a.primary
+	}
+
+	if s := fetchSession(ctx); s != nil && s.within(a.window) {
+		return a.primary
+	}
+
+	return a.balancer.Replica(ctx, a.replicas)
+}
+
+// wrote marks session in the context as recently written.
+func (a *Adapter) wrote(ctx context.Context) {
+	if s := fetchSession(ctx); s != nil && a.window > 0 {
+		s.wrote()
+	}
+}
+
+// Aggregate using primary or replica database.
+func (a *Adapter) Aggregate(ctx context.Context, query rel.Query, mode string, field string) (int, error) {
+	return a.read(ctx, query).Aggregate(ctx, query, mode, field)
+}
+
+// Query using primary or replica database.
+func (a *Adapter) Query(ctx context.Context, query rel.Query) (rel.Cursor, error) {
+	return a.read(ctx, query).Query(ctx, query)
+}
+
+// Insert using primary database.
+func (a *Adapter) Insert(ctx context.Context, query rel.Query, primaryField string, mutates map[string]rel.Mutate, onConflict rel.OnConflict) (any, error) {
+	defer a.wrote(ctx)
+	return a.primary.Insert(ctx, query, primaryField, mutates, onConflict)
+}
+
+// InsertAll using primary database.
+func (a *Adapter) InsertAll(ctx context.Context, query rel.Query, primaryField string, fields []string, bulkMutates []map[string]rel.Mutate, onConflict rel.OnConflict) ([]any, error) {
+	defer a.wrote(ctx)
+	return a.primary.InsertAll(ctx, query, primaryField, fields, bulkMutates, onConflict)
+}
+
+// Update using primary database.
+func (a *Adapter) Update(ctx context.Context, query rel.Query, primaryField string, mutates map[string]rel.Mutate) (int, error) {
+	defer a.wrote(ctx)
+	return a.primary.Update(ctx, query, primaryField, mutates)
+}
+
+// Delete using primary database.
+func (a *Adapter) Delete(ctx context.Context, query rel.Query) (int, error) {
+	defer a.wrote(ctx)
+	return a.primary.Delete(ctx, query)
+}
+
+// Exec using primary database.
+func (a *Adapter) Exec(ctx context.Context, stmt string, args []any) (int64, int64, error) {
+	defer a.wrote(ctx)
+	return a.primary.Exec(ctx, stmt, args)
+}
+
+// Begin begins a new transaction using primary database.
+// Every operation inside the transaction is served by the primary.
+func (a *Adapter) Begin(ctx context.Context, options rel.TransactionOptions) (rel.Adapter, error) {
+	tx, err := a.primary.Begin(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction{Adapter: tx, wrote: a.wrote}, nil
+}
+
+// Commit using primary database.
+func (a *Adapter) Commit(ctx context.Context) error {
+	return a.primary.Commit(ctx)
+}
+
+// Rollback using primary database.
+func (a *Adapter) Rollback(ctx context.Context) error {
+	return a.primary.Rollback(ctx)
+}
+
+// Savepoint using primary database.
+func (a *Adapter) Savepoint(ctx context.Context, name string) error {
+	return a.primary.Savepoint(ctx, name)
+}
+
+// RollbackTo using primary database.
+func (a *Adapter) RollbackTo(ctx context.Context, name string) error {
+	return a.primary.RollbackTo(ctx, name)
+}
+
+// Release using primary database.
+func (a *Adapter) Release(ctx context.Context, name string) error {
+	return a.primary.Release(ctx, name)
+}
+
+// SupportPartitionLimit returns true if primary database supports partition limit,
+// replicas are expected to be the same kind of database.
+func (a *Adapter) SupportPartitionLimit() bool {
+	return rel.SupportPartitionLimit(a.primary)
+}
+
+// Apply migration using primary database.
+func (a *Adapter) Apply(ctx context.Context, migration rel.Migration) error {
+	return a.primary.Apply(ctx, migration)
+}
+
+// transaction of primary database, marks the session as written when it's committed.
+type transaction struct {
+	rel.Adapter
+	wrote func(ctx context.Context)
+}
+
+func (t *transaction) Commit(ctx context.Context) error {
+	defer t.wrote(ctx)
+	return t.Adapter.Commit(ctx)
+}
+
+func (t *transaction) SupportPartitionLimit() bool {
+	return rel.SupportPartitionLimit(t.Adapter)
+}
@@ -0,0 +1,168 @@
+package primaryreplica
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/memory"
+	"github.com/go-rel/rel/where"
+	"github.com/stretchr/testify/assert"
+)
+
+type User struct {
+	ID   int
+	Name string
+}
+
+func setup() (*Adapter, rel.Adapter, rel.Adapter) {
+	var (
+		primary = memory.New()
+		replica = memory.New()
+	)
+
+	rel.New(primary).MustInsertAll(context.TODO(), &[]User{{Name: "primary"}, {Name: "primary"}})
+	rel.New(replica).MustInsert(context.TODO(), &User{Name: "replica"})
+
+	return New(primary, replica), primary, replica
+}
+
+func TestAdapter(t *testing.T) {
+	var (
+		adapter, primary, replica = setup()
+	)
+
+	assert.Equal(t, "memory", adapter.Name())
+	assert.Same(t, primary, adapter.Primary())
+	assert.Equal(t, []rel.Adapter{replica}, adapter.Replicas())
+	assert.Nil(t, adapter.Ping(context.TODO()))
+	assert.True(t, adapter.SupportPartitionLimit())
+	assert.Nil(t, adapter.Close())
+}
+
+func TestAdapter_read(t *testing.T) {
+	var (
+		adapter, _, _ = setup()
+		repo          = rel.New(adapter)
+		user          User
+	)
+
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "users"))
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "users", rel.UsePrimary()))
+
+	assert.Nil(t, repo.Find(context.TODO(), &user))
+	assert.Equal(t, "replica", user.Name)
+
+	assert.Nil(t, repo.Find(context.TODO(), &user, rel.ForUpdate()))
+	assert.Equal(t, "primary", user.Name)
+}
+
+func TestAdapter_read_withoutReplica(t *testing.T) {
+	var (
+		_, primary, _ = setup()
+		repo          = rel.New(New(primary))
+	)
+
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "users"))
+}
+
+func TestAdapter_Balance(t *testing.T) {
+	var (
+		adapter, primary, replica = setup()
+		repo                      = rel.New(adapter)
+		replicas                  []rel.Adapter
+	)
+
+	adapter.Balance(BalancerFunc(func(ctx context.Context, r []rel.Adapter) rel.Adapter {
+		replicas = r
+		return primary
+	}))
+
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "users"))
+	assert.Equal(t, []rel.Adapter{replica}, replicas)
+}
+
+func TestAdapter_write(t *testing.T) {
+	var (
+		adapter, _, _ = setup()
+		repo          = rel.New(adapter)
+		user          = User{Name: "primary"}
+	)
+
+	repo.MustInsert(context.TODO(), &user)
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "users", rel.UsePrimary()))
+
+	user.Name = "updated"
+	repo.MustUpdate(context.TODO(), &user)
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "users", where.Eq("name", "updated"), rel.UsePrimary()))
+
+	repo.MustDelete(context.TODO(), &user)
+	assert.Equal(t, 2, repo.MustCount(context.TODO(), "users", rel.UsePrimary()))
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "users"))
+
+	_, _, err := adapter.Exec(context.TODO(), "DELETE FROM users", nil)
+	assert.ErrorIs(t, err, memory.ErrNotSupported)
+}
+
+func TestAdapter_Transaction(t *testing.T) {
+	var (
+		adapter, _, _ = setup()
+		repo          = rel.New(adapter)
+	)
+
+	assert.Nil(t, repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		assert.Equal(t, 2, repo.MustCount(ctx, "users"))
+		assert.True(t, repo.Adapter(ctx).(rel.PartitionLimiter).SupportPartitionLimit())
+
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			repo.MustInsert(ctx, &User{Name: "primary"})
+			assert.Equal(t, 3, repo.MustCount(ctx, "users"))
+			return nil
+		})
+	}))
+
+	assert.Equal(t, 3, repo.MustCount(context.TODO(), "users", rel.UsePrimary()))
+
+	assert.Equal(t, memory.ErrNoTransaction, adapter.Commit(context.TODO()))
+	assert.Equal(t, memory.ErrNoTransaction, adapter.Rollback(context.TODO()))
+	assert.Equal(t, memory.ErrNoTransaction, adapter.Savepoint(context.TODO(), "sp"))
+	assert.Equal(t, memory.ErrNoTransaction, adapter.RollbackTo(context.TODO(), "sp"))
+	assert.Equal(t, memory.ErrNoTransaction, adapter.Release(context.TODO(), "sp"))
+}
+
+func TestAdapter_ReadYourWrites(t *testing.T) {
+	var (
+		adapter, _, _ = setup()
+		repo          = rel.New(adapter)
+		ctx           = WithSession(context.TODO())
+		other         = WithSession(context.TODO())
+	)
+
+	adapter.ReadYourWrites(time.Hour)
+
+	assert.Equal(t, 1, repo.MustCount(ctx, "users"))
+
+	repo.MustInsert(ctx, &User{Name: "primary"})
+	assert.Equal(t, 3, repo.MustCount(ctx, "users"))
+	assert.Equal(t, 1, repo.MustCount(other, "users"))
+	assert.Equal(t, 1, repo.MustCount(context.TODO(), "users"))
+
+	assert.Nil(t, repo.Transaction(other, func(ctx context.Context) error {
+		return repo.Insert(ctx, &User{Name: "primary"})
+	}))
+	assert.Equal(t, 4, repo.MustCount(other, "users"))
+
+	adapter.ReadYourWrites(0)
+	assert.Equal(t, 1, repo.MustCount(ctx, "users"))
+}
+
+func TestAdapter_Apply(t *testing.T) {
+	var (
+		adapter, primary, replica = setup()
+	)
+
+	assert.Nil(t, adapter.Apply(context.TODO(), rel.Table{Op: rel.SchemaDrop, Name: "users"}))
+	assert.Equal(t, 0, rel.New(primary).MustCount(context.TODO(), "users"))
+	assert.Equal(t, 1, rel.New(replica).MustCount(context.TODO(), "users"))
+}
@@ -0,0 +1,44 @@
+package primaryreplica
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/go-rel/rel"
+)
+
+// Balancer chooses replica that serves a read query.
+// Replicas passed to balancer are never empty.
+type Balancer interface {
+	Replica(ctx context.Context, replicas []rel.Adapter) rel.Adapter
+}
+
+// BalancerFunc adapts ordinary function as a Balancer.
+type BalancerFunc func(ctx context.Context, replicas []rel.Adapter) rel.Adapter
+
+// Replica calls f(ctx, replicas).
+func (f BalancerFunc) Replica(ctx context.Context, replicas []rel.Adapter) rel.Adapter {
+	return f(ctx, replicas)
+}
+
+type roundRobin struct {
+	counter uint64
+}
+
+func (rr *roundRobin) Replica(ctx context.Context, replicas []rel.Adapter) rel.Adapter {
+	n := atomic.AddUint64(&rr.counter, 1) - 1
+	return replicas[n%uint64(len(replicas))]
+}
+
+// RoundRobin balancer picks replica in turn.
+func RoundRobin() Balancer {
+	return &roundRobin{}
+}
+
+// Random balancer picks replica randomly.
+func Random() Balancer {
+	return BalancerFunc(func(ctx context.Context, replicas []rel.Adapter) rel.Adapter {
+		return replicas[rand.Intn(len(replicas))]
+	})
+}
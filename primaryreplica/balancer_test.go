@@ -0,0 +1,32 @@
+package primaryreplica
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundRobin(t *testing.T) {
+	var (
+		balancer = RoundRobin()
+		replicas = []rel.Adapter{memory.New(), memory.New()}
+	)
+
+	assert.Same(t, replicas[0], balancer.Replica(context.TODO(), replicas))
+	assert.Same(t, replicas[1], balancer.Replica(context.TODO(), replicas))
+	assert.Same(t, replicas[0], balancer.Replica(context.TODO(), replicas))
+}
+
+func TestRandom(t *testing.T) {
+	var (
+		balancer = Random()
+		replicas = []rel.Adapter{memory.New(), memory.New()}
+	)
+
+	for i := 0; i < 10; i++ {
+		assert.Contains(t, replicas, balancer.Replica(context.TODO(), replicas))
+	}
+}
@@ -0,0 +1,42 @@
+package primaryreplica
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type contextKey int8
+
+var sessionKey contextKey
+
+// session tracks the last write made using a context.
+type session struct {
+	lock      sync.Mutex
+	lastWrite time.Time
+}
+
+func (s *session) wrote() {
+	s.lock.Lock()
+	s.lastWrite = time.Now()
+	s.lock.Unlock()
+}
+
+func (s *session) within(window time.Duration) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return !s.lastWrite.IsZero() && time.Since(s.lastWrite) < window
+}
+
+// WithSession returns context that tracks writes made using it.
+// When read your writes window is configured, reads using the returned context (or its children)
+// are served by the primary for the duration of the window after the last write.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey, &session{})
+}
+
+func fetchSession(ctx context.Context) *session {
+	s, _ := ctx.Value(sessionKey).(*session)
+	return s
+}
@@ -29,6 +29,8 @@ func Build(table string, queriers ...Querier) Query {
 		switch q := querier.(type) {
 		case Query:
 			q.Build(&query)
+		case WithQuery:
+			q.Build(&query)
 		case JoinQuery:
 			q.Build(&query)
 		case FilterQuery:
@@ -41,6 +43,10 @@ func Build(table string, queriers ...Querier) Query {
 			q.Build(&query)
 		case Limit:
 			q.Build(&query)
+		case PartitionLimit:
+			q.Build(&query)
+		case Keyset:
+			q.Build(&query)
 		case Lock:
 			q.Build(&query)
 		case Unscoped:
@@ -49,6 +55,8 @@ func Build(table string, queriers ...Querier) Query {
 			q.Build(&query)
 		case SQLQuery:
 			q.Build(&query)
+		case CombinationQuery:
+			q.Build(&query)
 		case Preload:
 			q.Build(&query)
 		case Cascade:
@@ -65,23 +73,27 @@ func Build(table string, queriers ...Querier) Query {
 
 // Query defines information about query generated by query builder.
 type Query struct {
-	empty           bool // TODO: use bitmask to mark what is updated and use it when merging two queries
-	Table           string
-	SelectQuery     SelectQuery
-	JoinQuery       []JoinQuery
-	WhereQuery      FilterQuery
-	GroupQuery      GroupQuery
-	SortQuery       []SortQuery
-	OffsetQuery     Offset
-	LimitQuery      Limit
-	LockQuery       Lock
-	SQLQuery        SQLQuery
-	UnscopedQuery   Unscoped
-	ReloadQuery     Reload
-	CascadeQuery    Cascade
-	PreloadQuery    []string
-	UsePrimaryDb    bool
-	queryPopulators []QueryPopulator
+	empty               bool // TODO: use bitmask to mark what is updated and use it when merging two queries
+	Table               string
+	WithQuery           []WithQuery
+	SelectQuery         SelectQuery
+	JoinQuery           []JoinQuery
+	WhereQuery          FilterQuery
+	GroupQuery          GroupQuery
+	SortQuery           []SortQuery
+	OffsetQuery         Offset
+	LimitQuery          Limit
+	PartitionLimitQuery PartitionLimit
+	KeysetQuery         Keyset
+	LockQuery           Lock
+	SQLQuery            SQLQuery
+	CombinationQuery    []CombinationQuery
+	UnscopedQuery       Unscoped
+	ReloadQuery         Reload
+	CascadeQuery        Cascade
+	PreloadQuery        []string
+	UsePrimaryDb        bool
+	queryPopulators     []QueryPopulator
 }
 
 // Build query.
@@ -94,10 +106,11 @@ func (q Query) Build(query *Query) {
 			query.Table = q.Table
 		}
 
-		if q.SelectQuery.Fields != nil {
+		if q.SelectQuery.Fields != nil || q.SelectQuery.Expressions != nil {
 			query.SelectQuery = q.SelectQuery
 		}
 
+		query.WithQuery = append(query.WithQuery, q.WithQuery...)
 		query.JoinQuery = append(query.JoinQuery, q.JoinQuery...)
 
 		if !q.WhereQuery.None() {
@@ -108,6 +121,7 @@ func (q Query) Build(query *Query) {
 			query.GroupQuery = q.GroupQuery
 		}
 
+		query.CombinationQuery = append(query.CombinationQuery, q.CombinationQuery...)
 		query.SortQuery = append(query.SortQuery, q.SortQuery...)
 
 		if q.OffsetQuery != 0 {
@@ -118,6 +132,14 @@ func (q Query) Build(query *Query) {
 			query.LimitQuery = q.LimitQuery
 		}
 
+		if q.PartitionLimitQuery != (PartitionLimit{}) {
+			query.PartitionLimitQuery = q.PartitionLimitQuery
+		}
+
+		if q.KeysetQuery != (Keyset{}) {
+			query.KeysetQuery = q.KeysetQuery
+		}
+
 		if q.LockQuery != "" {
 			query.LockQuery = q.LockQuery
 		}
@@ -133,6 +155,10 @@ func (q Query) Populate(documentMeta DocumentMeta) Query {
 		q.queryPopulators[i].Populate(&q, documentMeta)
 	}
 
+	if filter, ok := q.WhereQuery.populateAssoc(documentMeta); ok {
+		q.WhereQuery = filter
+	}
+
 	return q
 }
 
@@ -146,12 +172,31 @@ func (q Query) Select(fields ...string) Query {
 	return q
 }
 
+// With defines a common table expression that can be referenced by name in the query.
+func (q Query) With(name string, query Query) Query {
+	q.WithQuery = append(q.WithQuery, NewWith(name, query))
+	return q
+}
+
+// WithRecursive defines a recursive common table expression that can be referenced by name in the query.
+// Anchor and recursive query are combined using UNION ALL, and recursive query may reference the expression by its name.
+func (q Query) WithRecursive(name string, anchor Query, recursive Query) Query {
+	q.WithQuery = append(q.WithQuery, NewWithRecursive(name, anchor, recursive))
+	return q
+}
+
 // From set the table to be used for query.
 func (q Query) From(table string) Query {
 	q.Table = table
 	return q
 }
 
+// SelectExpr adds computed expressions to be selected from database.
+func (q Query) SelectExpr(exprs ...SelectExpression) Query {
+	q.SelectQuery.Expressions = append(q.SelectQuery.Expressions, exprs...)
+	return q
+}
+
 // Distinct sets select query to be distinct.
 func (q Query) Distinct() Query {
 	q.SelectQuery.OnlyDistinct = true
@@ -248,6 +293,42 @@ func (q Query) OrHavingf(expr string, args ...any) Query {
 	return q
 }
 
+// CombineWith combines result with another query using given set operator.
+func (q Query) CombineWith(operator string, query Query) Query {
+	q.CombinationQuery = append(q.CombinationQuery, NewCombination(operator, query))
+	return q
+}
+
+// Union combines result with another query using UNION, duplicate rows are removed.
+func (q Query) Union(query Query) Query {
+	return q.CombineWith("UNION", query)
+}
+
+// UnionAll combines result with another query using UNION ALL.
+func (q Query) UnionAll(query Query) Query {
+	return q.CombineWith("UNION ALL", query)
+}
+
+// Intersect combines result with another query using INTERSECT.
+func (q Query) Intersect(query Query) Query {
+	return q.CombineWith("INTERSECT", query)
+}
+
+// IntersectAll combines result with another query using INTERSECT ALL.
+func (q Query) IntersectAll(query Query) Query {
+	return q.CombineWith("INTERSECT ALL", query)
+}
+
+// Except combines result with another query using EXCEPT.
+func (q Query) Except(query Query) Query {
+	return q.CombineWith("EXCEPT", query)
+}
+
+// ExceptAll combines result with another query using EXCEPT ALL.
+func (q Query) ExceptAll(query Query) Query {
+	return q.CombineWith("EXCEPT ALL", query)
+}
+
 // Sort query.
 func (q Query) Sort(fields ...string) Query {
 	return q.SortAsc(fields...)
@@ -293,6 +374,24 @@ func (q Query) Limit(limit int) Query {
 	return q
 }
 
+// PartitionLimit result returned by database for each distinct value of the field.
+func (q Query) PartitionLimit(field string, limit int) Query {
+	q.PartitionLimitQuery = PartitionLimit{Field: field, Limit: limit}
+	return q
+}
+
+// After sets keyset pagination to fetch page after the cursor, used by FindPage.
+func (q Query) After(cursor string) Query {
+	q.KeysetQuery = After(cursor)
+	return q
+}
+
+// Before sets keyset pagination to fetch page before the cursor, used by FindPage.
+func (q Query) Before(cursor string) Query {
+	q.KeysetQuery = Before(cursor)
+	return q
+}
+
 // Lock query expression.
 func (q Query) Lock(lock string) Query {
 	q.LockQuery = Lock(lock)
@@ -342,6 +441,10 @@ func (q Query) String() string {
 		builder.WriteString(".UsePrimary()")
 	}
 
+	for _, wq := range q.WithQuery {
+		builder.WriteString(strings.TrimPrefix(wq.String(), "rel"))
+	}
+
 	if q.Table != "" {
 		builder.WriteString(".From(\"")
 		builder.WriteString(q.Table)
@@ -354,6 +457,18 @@ func (q Query) String() string {
 		builder.WriteString("\")")
 	}
 
+	if len(q.SelectQuery.Expressions) != 0 {
+		builder.WriteString(".SelectExpr(")
+		for i := range q.SelectQuery.Expressions {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+
+			builder.WriteString(q.SelectQuery.Expressions[i].String())
+		}
+		builder.WriteByte(')')
+	}
+
 	if q.SelectQuery.OnlyDistinct {
 		builder.WriteString(".Distinct()")
 	}
@@ -388,14 +503,25 @@ func (q Query) String() string {
 		}
 	}
 
+	for _, cq := range q.CombinationQuery {
+		builder.WriteString(strings.TrimPrefix(cq.String(), "rel"))
+	}
+
 	for _, sq := range q.SortQuery {
 		if sq.Asc() {
-			builder.WriteString(".SortAsc(\"")
+			builder.WriteString(".SortAsc(")
 		} else {
-			builder.WriteString(".SortDesc(\"")
+			builder.WriteString(".SortDesc(")
 		}
-		builder.WriteString(sq.Field)
-		builder.WriteString("\")")
+
+		if sq.Expr != nil {
+			builder.WriteString(sq.Expr.String())
+		} else {
+			builder.WriteByte('"')
+			builder.WriteString(sq.Field)
+			builder.WriteByte('"')
+		}
+		builder.WriteString(")")
 	}
 
 	if q.LimitQuery > 0 {
@@ -404,12 +530,32 @@ func (q Query) String() string {
 		builder.WriteString(")")
 	}
 
+	if q.PartitionLimitQuery.Limit > 0 {
+		builder.WriteString(".PartitionLimit(\"")
+		builder.WriteString(q.PartitionLimitQuery.Field)
+		builder.WriteString("\", ")
+		builder.WriteString(strconv.Itoa(q.PartitionLimitQuery.Limit))
+		builder.WriteString(")")
+	}
+
 	if q.OffsetQuery > 0 {
 		builder.WriteString(".Offset(")
 		builder.WriteString(strconv.Itoa(int(q.OffsetQuery)))
 		builder.WriteString(")")
 	}
 
+	if q.KeysetQuery.After != "" {
+		builder.WriteString(".After(\"")
+		builder.WriteString(q.KeysetQuery.After)
+		builder.WriteString("\")")
+	}
+
+	if q.KeysetQuery.Before != "" {
+		builder.WriteString(".Before(\"")
+		builder.WriteString(q.KeysetQuery.Before)
+		builder.WriteString("\")")
+	}
+
 	if q.LockQuery != "" {
 		builder.WriteString(".Lock(\"")
 		builder.WriteString(string(q.LockQuery))
@@ -453,6 +599,49 @@ func Select(fields ...string) Query {
 	return query
 }
 
+// With create a query with chainable syntax, using common table expression as the starting point.
+func With(name string, query Query) Query {
+	return newQuery().With(name, query)
+}
+
+// WithRecursive create a query with chainable syntax, using recursive common table expression as the starting point.
+func WithRecursive(name string, anchor Query, recursive Query) Query {
+	return newQuery().WithRecursive(name, anchor, recursive)
+}
+
+// Union create a query that combines result of all queries using UNION.
+func Union(query Query, queries ...Query) Query {
+	return combine("UNION", query, queries)
+}
+
+// UnionAll create a query that combines result of all queries using UNION ALL.
+func UnionAll(query Query, queries ...Query) Query {
+	return combine("UNION ALL", query, queries)
+}
+
+// Intersect create a query that combines result of all queries using INTERSECT.
+func Intersect(query Query, queries ...Query) Query {
+	return combine("INTERSECT", query, queries)
+}
+
+// Except create a query that returns result of the first query that are not returned by other queries.
+func Except(query Query, queries ...Query) Query {
+	return combine("EXCEPT", query, queries)
+}
+
+func combine(operator string, query Query, queries []Query) Query {
+	for i := range queries {
+		query = query.CombineWith(operator, queries[i])
+	}
+
+	return query
+}
+
+// SelectExpr create a query with chainable syntax, using computed select expressions as the starting point.
+func SelectExpr(exprs ...SelectExpression) Query {
+	return newQuery().SelectExpr(exprs...)
+}
+
 // From create a query with chainable syntax, using from as the starting point.
 func From(table string) Query {
 	query := newQuery()
@@ -538,6 +727,24 @@ func (l Limit) applyColumn(column *Column) {
 	column.Limit = int(l)
 }
 
+// PartitionLimit query.
+// It limits returned result for each distinct value of the field, the order is defined by sort query.
+type PartitionLimit struct {
+	Field string
+	Limit int
+}
+
+// Build query.
+func (pl PartitionLimit) Build(query *Query) {
+	query.PartitionLimitQuery = pl
+}
+
+// PreloadLimit limits preloaded records of each parent, field is filled with foreign field of the association.
+// Adapter that doesn't support partition limit is queried once for every parent.
+func PreloadLimit(limit int) PartitionLimit {
+	return PartitionLimit{Limit: limit}
+}
+
 // Lock query.
 // This query will be ignored if used outside of transaction.
 type Lock string
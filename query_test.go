@@ -13,6 +13,7 @@ import (
 
 func shallowAssertQuery(t *testing.T, a rel.Query, b rel.Query) {
 	assert.Equal(t, a.Table, b.Table)
+	assert.Equal(t, a.WithQuery, b.WithQuery)
 	assert.Equal(t, a.SelectQuery, b.SelectQuery)
 	assert.Equal(t, a.JoinQuery, b.JoinQuery)
 	assert.Equal(t, a.WhereQuery, b.WhereQuery)
@@ -20,8 +21,10 @@ func shallowAssertQuery(t *testing.T, a rel.Query, b rel.Query) {
 	assert.Equal(t, a.SortQuery, b.SortQuery)
 	assert.Equal(t, a.OffsetQuery, b.OffsetQuery)
 	assert.Equal(t, a.LimitQuery, b.LimitQuery)
+	assert.Equal(t, a.PartitionLimitQuery, b.PartitionLimitQuery)
 	assert.Equal(t, a.LockQuery, b.LockQuery)
 	assert.Equal(t, a.SQLQuery, b.SQLQuery)
+	assert.Equal(t, a.CombinationQuery, b.CombinationQuery)
 	assert.Equal(t, a.UnscopedQuery, b.UnscopedQuery)
 	assert.Equal(t, a.ReloadQuery, b.ReloadQuery)
 	assert.Equal(t, a.CascadeQuery, b.CascadeQuery)
@@ -101,6 +104,173 @@ func TestQuerier(t *testing.T) {
 				CascadeQuery: true,
 			},
 		},
+		{
+			name: "rel.Where(where.Match([]string{\"title\", \"body\"}, \"go orm\")).SortDesc(rel.Rank([]string{\"title\", \"body\"}, \"go orm\")).SortAsc(\"id\")",
+			queriers: [][]rel.Querier{
+				{
+					rel.Where(where.Match([]string{"title", "body"}, "go orm")), rel.SortRank([]string{"title", "body"}, "go orm"), rel.SortAsc("id"),
+				},
+				{
+					where.Match([]string{"title", "body"}, "go orm"), sort.Rank([]string{"title", "body"}, "go orm"), sort.Asc("id"),
+				},
+			},
+			query: rel.Query{
+				WhereQuery: where.Match([]string{"title", "body"}, "go orm"),
+				SortQuery: []rel.SortQuery{
+					{Expr: rel.Rank([]string{"title", "body"}, "go orm"), Sort: -1},
+					rel.SortAsc("id"),
+				},
+				CascadeQuery: true,
+			},
+		},
+		{
+			name: "rel.From(\"comments\").SortDesc(\"created_at\").PartitionLimit(\"post_id\", 3)",
+			queriers: [][]rel.Querier{
+				{
+					rel.From("comments").SortDesc("created_at").PartitionLimit("post_id", 3),
+				},
+				{
+					rel.From("comments"), rel.NewSortDesc("created_at"), rel.PartitionLimit{Field: "post_id", Limit: 3},
+				},
+			},
+			query: rel.Query{
+				Table:               "comments",
+				PartitionLimitQuery: rel.PartitionLimit{Field: "post_id", Limit: 3},
+				SortQuery:           []rel.SortQuery{rel.NewSortDesc("created_at")},
+				CascadeQuery:        true,
+			},
+		},
+		{
+			name: "rel.From(\"users\").SortAsc(\"name\").Limit(10).After(\"cursor\")",
+			queriers: [][]rel.Querier{
+				{
+					rel.From("users").Sort("name").Limit(10).After("cursor"),
+				},
+				{
+					rel.From("users"), rel.NewSortAsc("name"), rel.PageSize(10), rel.After("cursor"),
+				},
+			},
+			query: rel.Query{
+				Table:        "users",
+				LimitQuery:   10,
+				KeysetQuery:  rel.Keyset{After: "cursor"},
+				SortQuery:    []rel.SortQuery{rel.NewSortAsc("name")},
+				CascadeQuery: true,
+			},
+		},
+		{
+			name: "rel.With(\"adults\", rel.From(\"users\").Where(where.Gte(\"age\", 18))).From(\"adults\").SortAsc(\"name\")",
+			queriers: [][]rel.Querier{
+				{
+					rel.With("adults", rel.From("users").Where(where.Gte("age", 18))).From("adults").SortAsc("name"),
+				},
+				{
+					rel.NewWith("adults", rel.From("users").Where(where.Gte("age", 18))), rel.From("adults"), rel.NewSortAsc("name"),
+				},
+				{
+					rel.From("adults").SortAsc("name"), rel.With("adults", rel.From("users").Where(where.Gte("age", 18))),
+				},
+			},
+			query: rel.Query{
+				Table: "adults",
+				WithQuery: []rel.WithQuery{
+					{Name: "adults", Query: rel.From("users").Where(where.Gte("age", 18))},
+				},
+				SortQuery:    []rel.SortQuery{rel.NewSortAsc("name")},
+				CascadeQuery: true,
+			},
+		},
+		{
+			name: "rel.WithRecursive(\"tree\", rel.From(\"categories\").Where(where.Nil(\"parent_id\")), rel.From(\"categories\").JoinWith(\"JOIN\", \"tree\", \"tree.id\", \"categories.parent_id\")).From(\"tree\")",
+			queriers: [][]rel.Querier{
+				{
+					rel.WithRecursive("tree", rel.From("categories").Where(where.Nil("parent_id")), rel.From("categories").JoinOn("tree", "tree.id", "categories.parent_id")).From("tree"),
+				},
+				{
+					rel.NewWithRecursive("tree", rel.From("categories").Where(where.Nil("parent_id")), rel.From("categories").JoinOn("tree", "tree.id", "categories.parent_id")), rel.From("tree"),
+				},
+			},
+			query: rel.Query{
+				Table: "tree",
+				WithQuery: []rel.WithQuery{
+					{
+						Name:           "tree",
+						Query:          rel.From("categories").Where(where.Nil("parent_id")),
+						Recursive:      true,
+						RecursiveQuery: rel.From("categories").JoinOn("tree", "tree.id", "categories.parent_id"),
+					},
+				},
+				CascadeQuery: true,
+			},
+		},
+		{
+			name: "rel.From(\"users\").Select(\"name\").Union(rel.From(\"admins\").Select(\"name\")).SortAsc(\"name\").Limit(10)",
+			queriers: [][]rel.Querier{
+				{
+					rel.From("users").Select("name").Union(rel.From("admins").Select("name")).SortAsc("name").Limit(10),
+				},
+				{
+					rel.Union(rel.From("users").Select("name"), rel.From("admins").Select("name")), rel.NewSortAsc("name"), rel.Limit(10),
+				},
+				{
+					rel.From("users").Select("name"), rel.NewUnion(rel.From("admins").Select("name")), rel.NewSortAsc("name"), rel.Limit(10),
+				},
+			},
+			query: rel.Query{
+				Table:       "users",
+				SelectQuery: rel.NewSelect("name"),
+				CombinationQuery: []rel.CombinationQuery{
+					{Operator: "UNION", Query: rel.From("admins").Select("name")},
+				},
+				SortQuery:    []rel.SortQuery{rel.NewSortAsc("name")},
+				LimitQuery:   10,
+				CascadeQuery: true,
+			},
+		},
+		{
+			name: "rel.From(\"scores\").Select(\"user_id\").SelectExpr(rel.Fn(\"row_number\").Over().PartitionBy(\"user_id\").OrderBy(rel.SortDesc(\"score\")).As(\"rank\"))",
+			queriers: [][]rel.Querier{
+				{
+					rel.From("scores").Select("user_id").SelectExpr(rel.Fn("row_number").Over().PartitionBy("user_id").OrderBy(rel.SortDesc("score")).As("rank")),
+				},
+				{
+					rel.SelectExpr(rel.Fn("row_number").Over().PartitionBy("user_id").OrderBy(rel.SortDesc("score")).As("rank")).From("scores"), rel.Select("user_id").SelectExpr(rel.Fn("row_number").Over().PartitionBy("user_id").OrderBy(rel.SortDesc("score")).As("rank")),
+				},
+			},
+			query: rel.Query{
+				Table: "scores",
+				SelectQuery: rel.SelectQuery{
+					Fields: []string{"user_id"},
+					Expressions: []rel.SelectExpression{
+						{
+							Expr: rel.WindowExpr{
+								Func:      rel.FuncExpr{Name: "row_number"},
+								Partition: []string{"user_id"},
+								SortQuery: []rel.SortQuery{rel.SortDesc("score")},
+							},
+							Alias: "rank",
+						},
+					},
+				},
+				CascadeQuery: true,
+			},
+		},
+		{
+			name: "rel.From(\"users\").Before(\"cursor\")",
+			queriers: [][]rel.Querier{
+				{
+					rel.From("users").Before("cursor"),
+				},
+				{
+					rel.From("users"), rel.Before("cursor"),
+				},
+			},
+			query: rel.Query{
+				Table:        "users",
+				KeysetQuery:  rel.Keyset{Before: "cursor"},
+				CascadeQuery: true,
+			},
+		},
 		{
 			name: "rel.From(\"transactions\").Select(\"sum(amount)\", \"name\").JoinWith(\"JOIN\", \"users\", \"\", \"\").Group(\"name\").Having(where.Gt(\"amount\", 10)).Limit(5).Offset(10)",
 			queriers: [][]rel.Querier{
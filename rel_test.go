@@ -133,6 +133,26 @@ type UserRole struct {
 	RoleID int `db:",primary"`
 }
 
+type Tag struct {
+	ID   int
+	Name string
+}
+
+type PostTag struct {
+	PostID int `db:",primary"`
+	TagID  int `db:",primary"`
+}
+
+type Post struct {
+	ID       int
+	Title    string
+	PostTags []PostTag
+
+	// many to many with autosave:
+	// post:id <- post_id:post_tags:tag_id -> tag:id
+	Tags []Tag `through:"post_tags" autosave:"true"`
+}
+
 type UserRepository struct {
 	ID        int
 	Name      string
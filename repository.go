@@ -5,7 +5,9 @@ import (
 	"errors"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // Repository for interacting with database.
@@ -21,9 +23,25 @@ type Repository interface {
 
 	// Iterate through a collection of entities from database in batches.
 	// This function returns iterator that can be used to loop all entities.
+	// Entities are iterated in order of primary fields using keyset pagination.
 	// Limit, Offset and Sort query is automatically ignored.
 	Iterate(ctx context.Context, query Query, option ...IteratorOption) Iterator
 
+	// IterateParallel splits iteration into partitions by range of primary field, and calls fn for each partition concurrently.
+	// Partition ranges are computed using min and max aggregate of the primary field,
+	// so entity must have a single integer primary field. Start and Finish option is overridden by the partition range.
+	// Context passed to fn is canceled as soon as any fn returns an error, and the first error is returned.
+	// ErrParallelTransaction is returned when called inside transaction.
+	IterateParallel(ctx context.Context, query Query, workers int, fn func(ctx context.Context, it Iterator) error, option ...IteratorOption) error
+
+	// Stream entities that match the query from a single database query.
+	// Unlike FindAll, each entity is scanned lazily from the cursor when Next is called, so the result is never held in memory at once.
+	// Limit, Offset and Sort query are respected.
+	// Associations are never loaded while the cursor is open, Next returns ErrStreamPreload when the query preloads association,
+	// and autoload associations are not loaded.
+	// Iterator must be closed when no longer used to release the cursor.
+	Stream(ctx context.Context, query Query) Iterator
+
 	// Aggregate over the given field.
 	// Supported aggregate: count, sum, avg, max, min.
 	// Any select, group, offset, limit and sort query will be ignored automatically.
@@ -70,6 +88,16 @@ type Repository interface {
 	// It'll panic if any error occurred.
 	MustFindAndCountAll(ctx context.Context, entities any, queriers ...Querier) int
 
+	// FindPage of entities using keyset pagination.
+	// Page position is specified using After or Before cursor, and size is specified using PageSize.
+	// Primary fields are appended to the sort, so each entity has unique position.
+	// Sort fields must be present in the entity, and must not be nil.
+	FindPage(ctx context.Context, entities any, queriers ...Querier) (Page, error)
+
+	// MustFindPage of entities using keyset pagination.
+	// It'll panic if any error occurred.
+	MustFindPage(ctx context.Context, entities any, queriers ...Querier) Page
+
 	// Insert a entity to database.
 	Insert(ctx context.Context, entity any, mutators ...Mutator) error
 
@@ -139,6 +167,16 @@ type Repository interface {
 	// It'll panic if any error occurred.
 	MustPreload(ctx context.Context, entities any, field string, queriers ...Querier)
 
+	// PreloadCount counts has one or has many association without loading it.
+	// This function can accepts either a struct or a slice of structs.
+	// The count is assigned to field tagged with the association name, eg: `db:"comments_count,count:comments"`.
+	PreloadCount(ctx context.Context, entities any, field string, queriers ...Querier) error
+
+	// MustPreloadCount counts has one or has many association without loading it.
+	// This function can accept either a struct or a slice of structs.
+	// It'll panic if any error occurred.
+	MustPreloadCount(ctx context.Context, entities any, field string, queriers ...Querier)
+
 	// Exec raw statement.
 	// Returns last inserted id, rows affected and error.
 	Exec(ctx context.Context, statement string, args ...any) (int, int, error)
@@ -149,7 +187,12 @@ type Repository interface {
 
 	// Transaction performs transaction with given function argument.
 	// Transaction scope/connection is automatically passed using context.
-	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// Nested transaction is performed using savepoint, so failure inside inner transaction
+	// only rolls back changes made by the inner transaction.
+	// Options of nested transaction must be compatible with the outer transaction.
+	// Use Retry option to re-run the function when transaction failed with TransactionError,
+	// if context is done while waiting to retry, the returned error wraps both context and transaction error.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error, options ...TransactionOption) error
 }
 
 type repository struct {
@@ -178,15 +221,69 @@ func (r repository) Iterate(ctx context.Context, query Query, options ...Iterato
 	return newIterator(cw.ctx, cw.adapter, query, options)
 }
 
+func (r repository) Stream(ctx context.Context, query Query) Iterator {
+	var (
+		cw = fetchContext(ctx, r.rootAdapter)
+	)
+
+	return newStreamIterator(cw, r, query)
+}
+
+func (r repository) IterateParallel(ctx context.Context, query Query, workers int, fn func(ctx context.Context, it Iterator) error, options ...IteratorOption) error {
+	finish := r.instrumenter.Observe(ctx, "rel-iterate-parallel", "iterating entities in parallel")
+	defer finish(nil)
+
+	cw := fetchContext(ctx, r.rootAdapter)
+	if cw.depth > 0 {
+		return ErrParallelTransaction
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		wctx, stop = context.WithCancel(cw.ctx)
+		iterators  = newPartitionIterators(wctx, cw.adapter, query, workers, options)
+		wg         sync.WaitGroup
+		errOnce    sync.Once
+		err        error
+	)
+
+	defer stop()
+
+	wg.Add(len(iterators))
+	for i := range iterators {
+		go func(it Iterator) {
+			defer wg.Done()
+			defer it.Close()
+
+			if e := fn(wctx, it); e != nil {
+				errOnce.Do(func() {
+					err = e
+					stop()
+				})
+			}
+		}(iterators[i])
+	}
+
+	wg.Wait()
+
+	return err
+}
+
 func (r repository) Aggregate(ctx context.Context, query Query, aggregate string, field string) (int, error) {
 	finish := r.instrumenter.Observe(ctx, "rel-aggregate", "aggregating entities")
 	defer finish(nil)
 
+	mustResolvedAssoc(query.WhereQuery)
+
 	var (
 		cw = fetchContext(ctx, r.rootAdapter)
 	)
 
-	return r.aggregate(cw, query, aggregate, field)
+	query = withTenantScope(cw.ctx, tenantField(query.Table), query)
+	return r.aggregate(cw, withEntityScope(cw.ctx, query.Table, query), aggregate, field)
 }
 
 func (r repository) aggregate(cw contextWrapper, query Query, aggregate string, field string) (int, error) {
@@ -209,10 +306,14 @@ func (r repository) Count(ctx context.Context, collection string, queriers ...Qu
 	defer finish(nil)
 
 	var (
-		cw = fetchContext(ctx, r.rootAdapter)
+		cw    = fetchContext(ctx, r.rootAdapter)
+		query = Build(collection, queriers...)
 	)
 
-	return r.aggregate(cw, Build(collection, queriers...), "count", "*")
+	mustResolvedAssoc(query.WhereQuery)
+
+	query = withTenantScope(cw.ctx, tenantField(collection), query)
+	return r.aggregate(cw, withEntityScope(cw.ctx, collection, query), "count", "*")
 }
 
 func (r repository) MustCount(ctx context.Context, collection string, queriers ...Querier) int {
@@ -239,7 +340,7 @@ func (r repository) MustFind(ctx context.Context, entity any, queriers ...Querie
 }
 
 func (r repository) find(cw contextWrapper, doc *Document, query Query) error {
-	query = r.withDefaultScope(doc.meta, query, true)
+	query = r.withDefaultScope(cw.ctx, doc.meta, query, true)
 	cur, err := cw.adapter.Query(cw.ctx, query.Limit(1))
 	if err != nil {
 		return err
@@ -258,7 +359,7 @@ func (r repository) find(cw contextWrapper, doc *Document, query Query) error {
 		}
 	}
 
-	return nil
+	return afterFind(cw.ctx, doc)
 }
 
 func (r repository) FindAll(ctx context.Context, entities any, queriers ...Querier) error {
@@ -281,7 +382,15 @@ func (r repository) MustFindAll(ctx context.Context, entities any, queriers ...Q
 }
 
 func (r repository) findAll(cw contextWrapper, col *Collection, query Query) error {
-	query = r.withDefaultScope(col.meta, query, true)
+	query = r.withDefaultScope(cw.ctx, col.meta, query, true)
+	if err := r.queryAll(cw, col, query); err != nil {
+		return err
+	}
+
+	return r.preloadAll(cw, col, query)
+}
+
+func (r repository) queryAll(cw contextWrapper, col *Collection, query Query) error {
 	cur, err := cw.adapter.Query(cw.ctx, query)
 	if err != nil {
 		return err
@@ -294,13 +403,18 @@ func (r repository) findAll(cw contextWrapper, col *Collection, query Query) err
 	}
 	finish(nil)
 
+	return nil
+}
+
+// preloadAll preloads associations of scanned entities and calls after find hooks.
+func (r repository) preloadAll(cw contextWrapper, col *Collection, query Query) error {
 	for i := range query.PreloadQuery {
 		if err := r.preload(cw, col, query.PreloadQuery[i], nil); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return afterFindAll(cw.ctx, col)
 }
 
 func (r repository) FindAndCountAll(ctx context.Context, entities any, queriers ...Querier) (int, error) {
@@ -319,7 +433,7 @@ func (r repository) FindAndCountAll(ctx context.Context, entities any, queriers
 		return 0, err
 	}
 
-	return r.aggregate(cw, r.withDefaultScope(col.meta, query, false), "count", "*")
+	return r.aggregate(cw, r.withDefaultScope(cw.ctx, col.meta, query, false), "count", "*")
 }
 
 func (r repository) MustFindAndCountAll(ctx context.Context, entities any, queriers ...Querier) int {
@@ -329,6 +443,109 @@ func (r repository) MustFindAndCountAll(ctx context.Context, entities any, queri
 	return count
 }
 
+func (r repository) FindPage(ctx context.Context, entities any, queriers ...Querier) (Page, error) {
+	finish := r.instrumenter.Observe(ctx, "rel-find-page", "finding page of entities")
+	defer finish(nil)
+
+	var (
+		cw    = fetchContext(ctx, r.rootAdapter)
+		col   = NewCollection(entities)
+		query = Build(col.Table(), queriers...).Populate(col.Meta())
+	)
+
+	col.Reset()
+
+	return r.findPage(cw, col, query)
+}
+
+func (r repository) MustFindPage(ctx context.Context, entities any, queriers ...Querier) Page {
+	page, err := r.FindPage(ctx, entities, queriers...)
+	must(err)
+
+	return page
+}
+
+func (r repository) findPage(cw contextWrapper, col *Collection, query Query) (Page, error) {
+	var (
+		page   Page
+		size   = int(query.LimitQuery)
+		sorts  = keysetSorts(query.SortQuery, col.PrimaryFields())
+		before = query.KeysetQuery.Before != ""
+		token  = query.KeysetQuery.After
+	)
+
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+
+	if before {
+		token = query.KeysetQuery.Before
+	}
+
+	if token != "" {
+		values, err := decodePageCursor(token, sorts, col.meta)
+		if err != nil {
+			return page, err
+		}
+
+		query = query.Where(keysetFilter(sorts, values, before))
+	}
+
+	query.SortQuery = sorts
+	if before {
+		query.SortQuery = reverseSorts(sorts)
+	}
+
+	// fetch one more entity to check whether there's more page.
+	query.KeysetQuery = Keyset{}
+	query.OffsetQuery = 0
+	query.LimitQuery = Limit(size + 1)
+
+	query = r.withDefaultScope(cw.ctx, col.meta, query, true)
+	if err := r.queryAll(cw, col, query); err != nil {
+		return page, err
+	}
+
+	n := col.Len()
+	more := n > size
+	if more {
+		n = size
+		col.Truncate(0, n)
+	}
+
+	if before {
+		for i := 0; i < n/2; i++ {
+			col.Swap(i, n-i-1)
+		}
+	}
+
+	if n > 0 {
+		var (
+			err  error
+			prev = (before && more) || (!before && token != "")
+			next = (!before && more) || before
+		)
+
+		if prev {
+			if page.Prev, err = encodePageCursor(col.Get(0), sorts); err != nil {
+				return page, err
+			}
+		}
+
+		if next {
+			if page.Next, err = encodePageCursor(col.Get(n-1), sorts); err != nil {
+				return page, err
+			}
+		}
+	}
+
+	if err := r.preloadAll(cw, col, query); err != nil {
+		return Page{}, err
+	}
+
+	return page, nil
+}
+
 func (r repository) Insert(ctx context.Context, entity any, mutators ...Mutator) error {
 	finish := r.instrumenter.Observe(ctx, "rel-insert", "inserting a entity")
 	defer finish(nil)
@@ -344,7 +561,7 @@ func (r repository) Insert(ctx context.Context, entity any, mutators ...Mutator)
 	)
 
 	if !mutation.IsAssocEmpty() && mutation.Cascade == true {
-		return r.transaction(cw, func(cw contextWrapper) error {
+		return r.transaction(cw, transactionConfig{}, func(cw contextWrapper) error {
 			return r.insert(cw, doc, mutation)
 		})
 	}
@@ -359,6 +576,11 @@ func (r repository) insert(cw contextWrapper, doc *Document, mutation Mutation)
 		queriers = Build(doc.Table())
 	)
 
+	applyTenant(cw.ctx, doc, &mutation)
+	if err := beforeInsert(cw.ctx, doc, &mutation); err != nil {
+		return err
+	}
+
 	if mutation.Cascade {
 		if err := r.saveBelongsTo(cw, doc, &mutation); err != nil {
 			return err
@@ -389,7 +611,7 @@ func (r repository) insert(cw contextWrapper, doc *Document, mutation Mutation)
 		}
 	}
 
-	return nil
+	return afterInsert(cw.ctx, doc, &mutation)
 }
 
 func (r repository) MustInsert(ctx context.Context, entity any, mutators ...Mutator) {
@@ -443,6 +665,13 @@ func (r repository) insertAll(cw contextWrapper, col *Collection, mutation []Mut
 		bulkMutates = make([]map[string]Mutate, len(mutation))
 	)
 
+	for i := range mutation {
+		applyTenant(cw.ctx, col.Get(i), &mutation[i])
+		if err := beforeInsert(cw.ctx, col.Get(i), &mutation[i]); err != nil {
+			return err
+		}
+	}
+
 	// TODO: baypassable if it's predictable.
 	for i := range mutation {
 		for field := range mutation[i].Mutates {
@@ -470,6 +699,12 @@ func (r repository) insertAll(cw contextWrapper, col *Collection, mutation []Mut
 		}
 	}
 
+	for i := range mutation {
+		if err := afterInsert(cw.ctx, col.Get(i), &mutation[i]); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -489,7 +724,7 @@ func (r repository) Update(ctx context.Context, entity any, mutators ...Mutator)
 	)
 
 	if !mutation.IsAssocEmpty() && mutation.Cascade == true {
-		return r.transaction(cw, func(cw contextWrapper) error {
+		return r.transaction(cw, transactionConfig{}, func(cw contextWrapper) error {
 			return r.update(cw, doc, mutation, filter)
 		})
 	}
@@ -510,6 +745,10 @@ func (r repository) lockVersion(doc Document, unscoped Unscoped) (int, bool) {
 }
 
 func (r repository) update(cw contextWrapper, doc *Document, mutation Mutation, filter FilterQuery) error {
+	if err := beforeUpdate(cw.ctx, doc, &mutation); err != nil {
+		return err
+	}
+
 	if mutation.Cascade {
 		if err := r.saveBelongsTo(cw, doc, &mutation); err != nil {
 			return err
@@ -532,7 +771,7 @@ func (r repository) update(cw contextWrapper, doc *Document, mutation Mutation,
 		}
 	}
 
-	return nil
+	return afterUpdate(cw.ctx, doc, &mutation)
 }
 
 func (r repository) applyMutates(cw contextWrapper, doc *Document, mutation Mutation, filter FilterQuery) (dbErr error) {
@@ -553,7 +792,7 @@ func (r repository) applyMutates(cw contextWrapper, doc *Document, mutation Muta
 
 	var (
 		pField string
-		query  = r.withDefaultScope(doc.meta, Build(doc.Table(), queries...).Populate(doc.Meta()), false)
+		query  = r.withDefaultScope(cw.ctx, doc.meta, Build(doc.Table(), queries...).Populate(doc.Meta()), false)
 	)
 
 	if len(doc.meta.primaryField) == 1 {
@@ -567,7 +806,7 @@ func (r repository) applyMutates(cw contextWrapper, doc *Document, mutation Muta
 	}
 
 	if mutation.Reload {
-		baseQuery := r.withDefaultScope(doc.meta, Build(doc.Table(), baseQueries...).Populate(doc.Meta()), false)
+		baseQuery := r.withDefaultScope(cw.ctx, doc.meta, Build(doc.Table(), baseQueries...).Populate(doc.Meta()), false)
 		if err := r.find(cw, doc, baseQuery.UsePrimary()); err != nil {
 			return err
 		}
@@ -618,6 +857,11 @@ func (r repository) saveBelongsTo(cw contextWrapper, doc *Document, mutation *Mu
 
 			mutation.Add(Set(rField, fValue))
 			doc.SetValue(rField, fValue)
+
+			if pField := assoc.PolymorphicField(); pField != "" {
+				mutation.Add(Set(pField, assocDoc.Table()))
+				doc.SetValue(pField, assocDoc.Table())
+			}
 		}
 	}
 
@@ -659,6 +903,11 @@ func (r repository) saveHasOne(cw contextWrapper, doc *Document, mutation *Mutat
 			assocMut.Add(Set(fField, rValue))
 			assocDoc.SetValue(fField, rValue)
 
+			if pField := assoc.PolymorphicField(); pField != "" {
+				assocMut.Add(Set(pField, assoc.PolymorphicValue()))
+				assocDoc.SetValue(pField, assoc.PolymorphicValue())
+			}
+
 			if err := r.insert(cw, assocDoc, assocMut); err != nil {
 				return err
 			}
@@ -680,6 +929,14 @@ func (r repository) saveHasMany(cw contextWrapper, doc *Document, mutation *Muta
 			continue
 		}
 
+		if assoc.Through() != "" {
+			if err := r.saveHasManyThrough(cw, doc, assoc, assocMuts, insertion); err != nil {
+				return err
+			}
+
+			continue
+		}
+
 		var (
 			col, _     = assoc.Collection()
 			table      = col.Table()
@@ -696,17 +953,17 @@ func (r repository) saveHasMany(cw contextWrapper, doc *Document, mutation *Muta
 
 		if !insertion {
 			var (
-				filter = Eq(fField, rValue)
+				filter = filterPolymorphic(assoc, Eq(fField, rValue))
 			)
 
 			if deletedIDs == nil {
 				// if it's nil, then clear old association (used by structset).
-				if _, err := r.deleteAny(cw, col.meta.flag, Build(table, filter).Populate(col.Meta())); err != nil {
+				if _, err := r.deleteAny(cw, col.meta.flag, col.meta.tenantField, Build(table, filter).Populate(col.Meta())); err != nil {
 					return err
 				}
 			} else if len(deletedIDs) > 0 {
 				filter = filter.AndIn(col.PrimaryField(), deletedIDs...)
-				if _, err := r.deleteAny(cw, col.meta.flag, Build(table, filter).Populate(col.Meta())); err != nil {
+				if _, err := r.deleteAny(cw, col.meta.flag, col.meta.tenantField, Build(table, filter).Populate(col.Meta())); err != nil {
 					return err
 				}
 			}
@@ -724,7 +981,7 @@ func (r repository) saveHasMany(cw contextWrapper, doc *Document, mutation *Muta
 			var fValue, _ = assocDoc.Value(fField)
 			if deletedIDs != nil && !isZero(assocDoc.PrimaryValue()) && !isZero(fValue) {
 				var (
-					filter = filterDocument(assocDoc).AndEq(fField, rValue)
+					filter = filterPolymorphic(assoc, filterDocument(assocDoc).AndEq(fField, rValue))
 				)
 
 				if rValue != fValue {
@@ -748,6 +1005,11 @@ func (r repository) saveHasMany(cw contextWrapper, doc *Document, mutation *Muta
 			} else {
 				muts[i].Add(Set(fField, rValue))
 				assocDoc.SetValue(fField, rValue)
+
+				if pField := assoc.PolymorphicField(); pField != "" {
+					muts[i].Add(Set(pField, assoc.PolymorphicValue()))
+					assocDoc.SetValue(pField, assoc.PolymorphicValue())
+				}
 			}
 		}
 
@@ -772,10 +1034,101 @@ func (r repository) saveHasMany(cw contextWrapper, doc *Document, mutation *Muta
 	return nil
 }
 
+// saveHasManyThrough saves targets of many to many association and manages its rows in intermediary table.
+// New targets are inserted and loaded targets are updated, then links of removed targets are deleted,
+// and links of the remaining targets that doesn't exist yet are inserted.
+func (r repository) saveHasManyThrough(cw contextWrapper, doc *Document, assoc Association, assocMuts AssocMutation, insertion bool) error {
+	var (
+		col, _       = assoc.Collection()
+		muts         = assocMuts.Mutations
+		deletedIDs   = assocMuts.DeletedIDs
+		through      = doc.Association(assoc.Through())
+		throughMeta  = through.meta.DocumentMeta()
+		throughField = through.ForeignField()
+		rValue       = through.ReferenceValue()
+		targetField  = throughTargetField(throughMeta, throughField, col.meta)
+		fField       = assoc.ForeignField()
+		fValues      = make([]any, 0, col.Len())
+		linked       = make(map[any]struct{}, col.Len())
+	)
+
+	// this shouldn't happen unless there's bug in the mutator.
+	if len(muts) != col.Len() {
+		panic("rel: invalid mutator")
+	}
+
+	for i := range muts {
+		var (
+			targetDoc = col.Get(i)
+		)
+
+		if fValue, _ := targetDoc.Value(fField); isZero(fValue) {
+			if err := r.insert(cw, targetDoc, muts[i]); err != nil {
+				return err
+			}
+		} else if !muts[i].IsEmpty() {
+			if err := r.update(cw, targetDoc, muts[i], filterDocument(targetDoc)); err != nil {
+				return err
+			}
+		}
+
+		fValue, _ := targetDoc.Value(fField)
+		fValues = append(fValues, fValue)
+	}
+
+	if !insertion {
+		var (
+			filter = Eq(throughField, rValue)
+		)
+
+		if deletedIDs == nil {
+			// if it's nil, then replace old links (used by structset).
+			if _, err := r.deleteAny(cw, throughMeta.flag, throughMeta.tenantField, Build(throughMeta.Table(), filter).Populate(throughMeta)); err != nil {
+				return err
+			}
+		} else if len(deletedIDs) > 0 {
+			if _, err := r.deleteAny(cw, throughMeta.flag, throughMeta.tenantField, Build(throughMeta.Table(), filter.AndIn(targetField, deletedIDs...)).Populate(throughMeta)); err != nil {
+				return err
+			}
+		}
+
+		// skip links that already exists.
+		if deletedIDs != nil && len(fValues) > 0 {
+			existing := NewCollection(reflect.New(reflect.SliceOf(throughMeta.rt)).Interface())
+			if err := r.findAll(cw, existing, Build(throughMeta.Table(), filter.AndIn(targetField, fValues...))); err != nil {
+				return err
+			}
+
+			for i := 0; i < existing.Len(); i++ {
+				value, _ := existing.Get(i).Value(targetField)
+				linked[value] = struct{}{}
+			}
+		}
+	}
+
+	var (
+		links     = NewCollection(reflect.New(reflect.SliceOf(throughMeta.rt)).Interface())
+		linksMuts []Mutation
+	)
+
+	for _, fValue := range fValues {
+		if _, ok := linked[fValue]; ok {
+			continue
+		}
+
+		linked[fValue] = struct{}{}
+		linksMuts = append(linksMuts, Apply(links.Add(), Set(throughField, rValue), Set(targetField, fValue)))
+	}
+
+	return r.insertAll(cw, links, linksMuts)
+}
+
 func (r repository) UpdateAny(ctx context.Context, query Query, mutates ...Mutate) (int, error) {
 	finish := r.instrumenter.Observe(ctx, "rel-update-any", "updating multiple entities")
 	defer finish(nil)
 
+	mustResolvedAssoc(query.WhereQuery)
+
 	var (
 		err          error
 		updatedCount int
@@ -787,8 +1140,12 @@ func (r repository) UpdateAny(ctx context.Context, query Query, mutates ...Mutat
 		muts[mut.Field] = mut
 	}
 
+	if query, err = requireTenantScope(cw.ctx, query); err != nil {
+		return 0, err
+	}
+
 	if len(muts) > 0 {
-		updatedCount, err = cw.adapter.Update(cw.ctx, query, "", muts)
+		updatedCount, err = cw.adapter.Update(cw.ctx, withEntityScope(cw.ctx, query.Table, query), "", muts)
 	}
 
 	return updatedCount, err
@@ -811,7 +1168,7 @@ func (r repository) Delete(ctx context.Context, entity any, mutators ...Mutator)
 	)
 
 	if mutation.Cascade {
-		return r.transaction(cw, func(cw contextWrapper) error {
+		return r.transaction(cw, transactionConfig{}, func(cw contextWrapper) error {
 			return r.delete(cw, doc, filterDocument(doc), mutation)
 		})
 	}
@@ -820,6 +1177,10 @@ func (r repository) Delete(ctx context.Context, entity any, mutators ...Mutator)
 }
 
 func (r repository) delete(cw contextWrapper, doc *Document, filter FilterQuery, mutation Mutation) error {
+	if err := beforeDelete(cw.ctx, doc, &mutation); err != nil {
+		return err
+	}
+
 	var filters []Querier = []Querier{filter, mutation.Unscoped}
 
 	if version, ok := r.lockVersion(*doc, mutation.Unscoped); ok {
@@ -841,7 +1202,7 @@ func (r repository) delete(cw contextWrapper, doc *Document, filter FilterQuery,
 		}
 	}
 
-	deletedCount, err := r.deleteAny(cw, doc.meta.flag, query)
+	deletedCount, err := r.deleteAny(cw, doc.meta.flag, doc.meta.tenantField, query)
 	if err == nil && deletedCount == 0 {
 		err = NotFoundError{}
 	}
@@ -852,7 +1213,11 @@ func (r repository) delete(cw contextWrapper, doc *Document, filter FilterQuery,
 		}
 	}
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	return afterDelete(cw.ctx, doc, &mutation)
 }
 
 func (r repository) deleteBelongsTo(cw contextWrapper, doc *Document, cascade Cascade) error {
@@ -916,14 +1281,29 @@ func (r repository) deleteHasMany(cw contextWrapper, doc *Document) error {
 		}
 
 		if col, loaded := assoc.Collection(); loaded && col.Len() != 0 {
+			// only links in intermediary table are deleted, targets may be associated with other entities.
+			if assoc.Through() != "" {
+				var (
+					through     = doc.Association(assoc.Through())
+					throughMeta = through.meta.DocumentMeta()
+					filter      = Eq(through.ForeignField(), through.ReferenceValue())
+				)
+
+				if _, err := r.deleteAny(cw, throughMeta.flag, throughMeta.tenantField, Build(throughMeta.Table(), filter).Populate(throughMeta)); err != nil {
+					return err
+				}
+
+				continue
+			}
+
 			var (
 				table  = col.Table()
 				fField = assoc.ForeignField()
 				rValue = assoc.ReferenceValue()
-				filter = Eq(fField, rValue).And(filterCollection(col))
+				filter = filterPolymorphic(assoc, Eq(fField, rValue)).And(filterCollection(col))
 			)
 
-			if _, err := r.deleteAny(cw, col.meta.flag, Build(table, filter).Populate(doc.Meta())); err != nil {
+			if _, err := r.deleteAny(cw, col.meta.flag, col.meta.tenantField, Build(table, filter).Populate(doc.Meta())); err != nil {
 				return err
 			}
 		}
@@ -951,7 +1331,7 @@ func (r repository) DeleteAll(ctx context.Context, entities any) error {
 
 	var (
 		query  = Build(col.Table(), filterCollection(col)).Populate(col.Meta())
-		_, err = r.deleteAny(cw, col.meta.flag, query)
+		_, err = r.deleteAny(cw, col.meta.flag, col.meta.tenantField, query)
 	)
 
 	return err
@@ -965,11 +1345,20 @@ func (r repository) DeleteAny(ctx context.Context, query Query) (int, error) {
 	finish := r.instrumenter.Observe(ctx, "rel-delete-any", "deleting multiple entities")
 	defer finish(nil)
 
+	mustResolvedAssoc(query.WhereQuery)
+
 	var (
-		cw = fetchContext(ctx, r.rootAdapter)
+		cw    = fetchContext(ctx, r.rootAdapter)
+		field = tenantField(query.Table)
 	)
 
-	return r.deleteAny(cw, Invalid, query)
+	if field != "" {
+		if _, ok := TenantFromContext(ctx); !ok {
+			return 0, ErrTenantRequired
+		}
+	}
+
+	return r.deleteAny(cw, Invalid, field, query)
 }
 
 func (r repository) MustDeleteAny(ctx context.Context, query Query) int {
@@ -978,7 +1367,8 @@ func (r repository) MustDeleteAny(ctx context.Context, query Query) int {
 	return deletedCount
 }
 
-func (r repository) deleteAny(cw contextWrapper, flag DocumentFlag, query Query) (int, error) {
+func (r repository) deleteAny(cw contextWrapper, flag DocumentFlag, field string, query Query) (int, error) {
+	query = withTenantScope(cw.ctx, field, query)
 	hasDeletedAt := flag.Is(HasDeletedAt)
 	hasDeleted := flag.Is(HasDeleted)
 	mutates := make(map[string]Mutate, 1)
@@ -1006,31 +1396,49 @@ func (r repository) Preload(ctx context.Context, entities any, field string, que
 	defer finish(nil)
 
 	var (
-		sl slice
 		cw = fetchContext(ctx, r.rootAdapter)
-		rt = reflect.TypeOf(entities)
 	)
 
+	return r.preload(cw, newSlice(entities), field, queriers)
+}
+
+// newSlice returns collection if entities is a pointer to slice, otherwise document.
+func newSlice(entities any) slice {
+	rt := reflect.TypeOf(entities)
 	if rt.Kind() != reflect.Ptr {
 		panic("rel: entity parameter must be a pointer.")
 	}
 
-	rt = rt.Elem()
-	if rt.Kind() == reflect.Slice {
-		sl = NewCollection(entities)
-	} else {
-		sl = NewDocument(entities)
+	if rt.Elem().Kind() == reflect.Slice {
+		return NewCollection(entities)
 	}
 
-	return r.preload(cw, sl, field, queriers)
+	return NewDocument(entities)
 }
 
 func (r repository) preload(cw contextWrapper, entities slice, field string, queriers []Querier) error {
 	var (
-		path                                             = strings.Split(field, ".")
-		targets, table, keyField, keyType, ddata, loaded = r.mapPreloadTargets(entities, path)
-		ids                                              = r.targetIDs(targets)
-		inClauseLength                                   = 999
+		path         = strings.Split(field, ".")
+		targets, err = r.mapPreloadTargets(entities, path)
+	)
+
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if err := r.preloadTargets(cw, entities, target, queriers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r repository) preloadTargets(cw contextWrapper, entities slice, target *preloadTarget, queriers []Querier) error {
+	var (
+		ids            = r.targetIDs(target.targets)
+		inClauseLength = 999
 	)
 
 	// Create separate queries if the amount of ids is more than inClauseLength.
@@ -1048,51 +1456,178 @@ func (r repository) preload(cw contextWrapper, entities slice, field string, que
 		idsChunk := ids[0:inClauseLength]
 		ids = ids[inClauseLength:]
 
-		query := Build(table, append(queriers, In(keyField, idsChunk...))...).Populate(entities.Meta())
-		if len(targets) == 0 || loaded && !bool(query.ReloadQuery) {
+		query := r.preloadQuery(target, queriers, idsChunk)
+		if len(target.targets) == 0 || target.loaded && !bool(query.ReloadQuery) {
 			return nil
 		}
 
-		var (
-			cur, err = cw.adapter.Query(cw.ctx, r.withDefaultScope(ddata, query, false))
-		)
+		if query.PartitionLimitQuery.Limit <= 0 || SupportPartitionLimit(cw.adapter) {
+			if err := r.preloadScan(cw, target, query); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// Fallback to one query for each parent when adapter doesn't support partition limit.
+		for _, id := range idsChunk {
+			query := r.preloadQuery(target, queriers, []any{id})
+			query.LimitQuery = Limit(query.PartitionLimitQuery.Limit)
+			query.PartitionLimitQuery = PartitionLimit{}
+
+			if err := r.preloadScan(cw, target, query); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r repository) preloadQuery(target *preloadTarget, queriers []Querier, ids []any) Query {
+	query := Build(target.table, append(queriers, In(target.keyField, ids...))...)
+	if target.polymorphicField != "" {
+		query = query.Where(Eq(target.polymorphicField, target.polymorphicValue))
+	}
+
+	if query.PartitionLimitQuery.Limit > 0 && query.PartitionLimitQuery.Field == "" {
+		query.PartitionLimitQuery.Field = target.keyField
+	}
+
+	return query.Populate(target.meta)
+}
+
+func (r repository) preloadScan(cw contextWrapper, target *preloadTarget, query Query) error {
+	cur, err := cw.adapter.Query(cw.ctx, r.withDefaultScope(cw.ctx, target.meta, query, false))
+	if err != nil {
+		return err
+	}
+
+	scanFinish := r.instrumenter.Observe(cw.ctx, "rel-scan-multi", "scanning all entities to multiple targets")
+	// Note: Calling scanMulti multiple times with the same targets works
+	// only if the cursor of each execution only contains a new set of keys.
+	// That is here the case as each select is with a unique set of ids.
+	err = scanMulti(cur, target.keyField, target.keyType, target.targets)
+	scanFinish(err)
+
+	return err
+}
+
+func (r repository) MustPreload(ctx context.Context, entities any, field string, queriers ...Querier) {
+	must(r.Preload(ctx, entities, field, queriers...))
+}
+
+func (r repository) PreloadCount(ctx context.Context, entities any, field string, queriers ...Querier) error {
+	finish := r.instrumenter.Observe(ctx, "rel-preload-count", "counting associations")
+	defer finish(nil)
+
+	var (
+		cw = fetchContext(ctx, r.rootAdapter)
+	)
+
+	return r.preloadCount(cw, newSlice(entities), field, queriers)
+}
+
+func (r repository) preloadCount(cw contextWrapper, entities slice, field string, queriers []Querier) error {
+	var (
+		meta           = entities.Meta()
+		countField, ok = meta.CountField(field)
+		assocMeta      = meta.Association(field)
+		refField       = assocMeta.ReferenceField()
+		fkField        = assocMeta.ForeignField()
+		keyType        reflect.Type
+		counts         = make(map[any]int)
+		ids            []any
+		inClauseLength = 999
+	)
+
+	if !ok {
+		panic("rel: count field of association " + field + " is not defined")
+	}
+
+	if assocMeta.Type() == BelongsTo || assocMeta.Through() != "" {
+		panic("rel: count is only supported for has one and has many association")
+	}
+
+	for i := 0; i < entities.Len(); i++ {
+		doc := entities.Get(i)
+		doc.SetValue(countField, 0)
+
+		if ref, ok := doc.Value(refField); ok && !isZero(ref) {
+			if _, exists := counts[ref]; !exists {
+				counts[ref] = 0
+				ids = append(ids, ref)
+				keyType = reflect.TypeOf(ref)
+			}
+		}
+	}
+
+	for len(ids) > 0 {
+		if len(ids) < inClauseLength {
+			inClauseLength = len(ids)
+		}
+
+		query := Build(assocMeta.DocumentMeta().Table(), append(queriers, In(fkField, ids[:inClauseLength]...))...)
+		if assocMeta.PolymorphicField() != "" {
+			query = query.Where(Eq(assocMeta.PolymorphicField(), assocMeta.PolymorphicValue()))
+		}
+
+		ids = ids[inClauseLength:]
 
+		query.SortQuery = nil
+		query.LimitQuery = 0
+		query.OffsetQuery = 0
+		query = query.Select(fkField).SelectExpr(Fn("count", Field("*")).As("count")).Group(fkField)
+		query = query.Populate(assocMeta.DocumentMeta())
+
+		cur, err := cw.adapter.Query(cw.ctx, r.withDefaultScope(cw.ctx, assocMeta.DocumentMeta(), query, false))
 		if err != nil {
 			return err
 		}
 
-		scanFinish := r.instrumenter.Observe(cw.ctx, "rel-scan-multi", "scanning all entities to multiple targets")
-		// Note: Calling scanMulti multiple times with the same targets works
-		// only if the cursor of each execution only contains a new set of keys.
-		// That is here the case as each select is with a unique set of ids.
-		err = scanMulti(cur, keyField, keyType, targets)
-		scanFinish(err)
-		if err != nil {
+		if err := scanCount(cur, fkField, keyType, counts); err != nil {
 			return err
 		}
 	}
 
+	for i := 0; i < entities.Len(); i++ {
+		doc := entities.Get(i)
+		if ref, ok := doc.Value(refField); ok {
+			doc.SetValue(countField, counts[ref])
+		}
+	}
+
 	return nil
 }
 
-func (r repository) MustPreload(ctx context.Context, entities any, field string, queriers ...Querier) {
-	must(r.Preload(ctx, entities, field, queriers...))
+func (r repository) MustPreloadCount(ctx context.Context, entities any, field string, queriers ...Querier) {
+	must(r.PreloadCount(ctx, entities, field, queriers...))
+}
+
+// preloadTarget groups preload targets that are queried from the same table.
+type preloadTarget struct {
+	table            string
+	keyField         string
+	keyType          reflect.Type
+	meta             DocumentMeta
+	polymorphicField string
+	polymorphicValue string
+	loaded           bool
+	targets          map[any][]slice
 }
 
-func (r repository) mapPreloadTargets(sl slice, path []string) (map[any][]slice, string, string, reflect.Type, DocumentMeta, bool) {
+// mapPreloadTargets maps association targets by reference value,
+// polymorphic belongs to association is grouped by its type, so each type is queried separately.
+func (r repository) mapPreloadTargets(sl slice, path []string) ([]*preloadTarget, error) {
 	type frame struct {
 		index int
 		doc   *Document
 	}
 
 	var (
-		table     string
-		keyField  string
-		keyType   reflect.Type
-		meta      DocumentMeta
-		loaded    = true
-		mapTarget = make(map[any][]slice)
-		stack     = make([]frame, sl.Len())
+		groups = make(map[string]*preloadTarget)
+		result []*preloadTarget
+		stack  = make([]frame, sl.Len())
 	)
 
 	// init stack
@@ -1113,6 +1648,8 @@ func (r repository) mapPreloadTargets(sl slice, path []string) (map[any][]slice,
 			var (
 				target       slice
 				targetLoaded bool
+				group        string
+				err          error
 				ref          = assocs.ReferenceValue()
 			)
 
@@ -1120,29 +1657,44 @@ func (r repository) mapPreloadTargets(sl slice, path []string) (map[any][]slice,
 				continue
 			}
 
-			if assocs.Type() == HasMany {
+			switch {
+			case assocs.Type() == HasMany:
 				target, targetLoaded = assocs.Collection()
-			} else {
+			case assocs.meta.isPolymorphicBelongsTo():
+				if group = assocs.PolymorphicValue(); group == "" {
+					continue
+				}
+
+				if target, targetLoaded, err = assocs.polymorphicTarget(); err != nil {
+					return nil, err
+				}
+			default:
 				target, targetLoaded = assocs.LazyDocument()
 			}
 
-			target.Reset()
-			mapTarget[ref] = append(mapTarget[ref], target)
-			loaded = loaded && targetLoaded
-
-			if table == "" {
-				table = target.Table()
-				keyField = assocs.ForeignField()
-				keyType = reflect.TypeOf(ref)
-
-				if doc, ok := target.(*Document); ok {
-					meta = doc.meta
+			pt, ok := groups[group]
+			if !ok {
+				pt = &preloadTarget{
+					table:    target.Table(),
+					keyField: assocs.ForeignField(),
+					keyType:  reflect.TypeOf(ref),
+					meta:     target.Meta(),
+					loaded:   true,
+					targets:  make(map[any][]slice),
 				}
 
-				if col, ok := target.(*Collection); ok {
-					meta = col.meta
+				if !assocs.meta.isPolymorphicBelongsTo() {
+					pt.polymorphicField = assocs.PolymorphicField()
+					pt.polymorphicValue = assocs.PolymorphicValue()
 				}
+
+				groups[group] = pt
+				result = append(result, pt)
 			}
+
+			target.Reset()
+			pt.targets[ref] = append(pt.targets[ref], target)
+			pt.loaded = pt.loaded && targetLoaded
 		} else {
 			if assocs.Type() == HasMany {
 				var (
@@ -1172,7 +1724,7 @@ func (r repository) mapPreloadTargets(sl slice, path []string) (map[any][]slice,
 
 	}
 
-	return mapTarget, table, keyField, keyType, meta, loaded
+	return result, nil
 }
 
 func (r repository) targetIDs(targets map[any][]slice) []any {
@@ -1189,15 +1741,31 @@ func (r repository) targetIDs(targets map[any][]slice) []any {
 	return ids
 }
 
-func (r repository) withDefaultScope(meta DocumentMeta, query Query, preload bool) Query {
+func (r repository) withDefaultScope(ctx context.Context, meta DocumentMeta, query Query, preload bool) Query {
+	// tenant is always scoped including nested queries, regardless of unscoped query.
+	query = withTenantScope(ctx, meta.tenantField, query)
 	if query.UnscopedQuery {
 		return query
 	}
 
-	if meta.flag.Is(HasDeleted) {
-		query = query.Where(Eq("deleted", false))
-	} else if meta.flag.Is(HasDeletedAt) {
-		query = query.Where(Nil("deleted_at"))
+	query = withEntityScope(ctx, meta.table, withSoftDeleteScope(meta, query))
+
+	// combined queries of the same table are scoped as well, copy to avoid modifying caller's query.
+	if len(query.CombinationQuery) > 0 {
+		combinations := make([]CombinationQuery, len(query.CombinationQuery))
+		for i, cq := range query.CombinationQuery {
+			if cq.Query.Table == "" {
+				cq.Query.Table = query.Table
+			}
+
+			if cq.Query.Table == query.Table && !cq.Query.UnscopedQuery {
+				cq.Query = withEntityScope(ctx, meta.table, withSoftDeleteScope(meta, cq.Query))
+			}
+
+			combinations[i] = cq
+		}
+
+		query.CombinationQuery = combinations
 	}
 
 	if preload && bool(query.CascadeQuery) {
@@ -1213,6 +1781,16 @@ func (r repository) withDefaultScope(meta DocumentMeta, query Query, preload boo
 	return query
 }
 
+func withSoftDeleteScope(meta DocumentMeta, query Query) Query {
+	if meta.flag.Is(HasDeleted) {
+		query = query.Where(Eq("deleted", false))
+	} else if meta.flag.Is(HasDeletedAt) {
+		query = query.Where(Nil("deleted_at"))
+	}
+
+	return query
+}
+
 // Exec raw statement.
 // Returns last inserted id, rows affected and error.
 func (r repository) Exec(ctx context.Context, stmt string, args ...any) (int, int, error) {
@@ -1228,7 +1806,7 @@ func (r repository) MustExec(ctx context.Context, stmt string, args ...any) (int
 	return lastInsertedId, rowsAffected
 }
 
-func (r repository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+func (r repository) Transaction(ctx context.Context, fn func(ctx context.Context) error, options ...TransactionOption) error {
 	finish := r.instrumenter.Observe(ctx, "rel-transaction", "transaction")
 	defer finish(nil)
 
@@ -1236,43 +1814,107 @@ func (r repository) Transaction(ctx context.Context, fn func(ctx context.Context
 		cw = fetchContext(ctx, r.rootAdapter)
 	)
 
-	return r.transaction(cw, func(cw contextWrapper) error {
+	return r.transaction(cw, applyTransactionOptions(options), func(cw contextWrapper) error {
 		return fn(cw.ctx)
 	})
 }
 
-func (r repository) transaction(cw contextWrapper, fn func(cw contextWrapper) error) error {
-	adp, err := cw.adapter.Begin(cw.ctx)
+func (r repository) transaction(cw contextWrapper, config transactionConfig, fn func(cw contextWrapper) error) error {
+	if cw.depth > 0 {
+		if !cw.options.compatible(config.options) {
+			return ErrIncompatibleTransaction
+		}
+
+		return r.savepoint(cw, fn)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := r.begin(cw, config.options, fn)
+		if err == nil || !config.retry.retryable(attempt, err) {
+			return err
+		}
+
+		finish := r.instrumenter.Observe(cw.ctx, "rel-transaction-retry", "retrying transaction")
+		finish(err)
+
+		if waitErr := config.retry.wait(cw.ctx, attempt); waitErr != nil {
+			return errors.Join(waitErr, err)
+		}
+	}
+}
+
+func (r repository) begin(cw contextWrapper, options TransactionOptions, fn func(cw contextWrapper) error) error {
+	adp, err := cw.adapter.Begin(cw.ctx, options)
 	if err != nil {
 		return err
 	}
 
+	var (
+		callbacks = &transactionCallbacks{}
+	)
+
 	// wrap trx adapter to new context.
-	cw = wrapContext(cw.ctx, adp)
+	cw = wrapContext(cw.ctx, contextData{adapter: adp, depth: 1, options: options, callbacks: callbacks})
 
-	func() {
-		defer func() {
-			if p := recover(); p != nil {
-				_ = cw.adapter.Rollback(cw.ctx)
+	return runTransaction(cw, fn, func(ctx context.Context) error {
+		err := adp.Commit(ctx)
+		callbacks.fire(err == nil)
+		return err
+	}, func(ctx context.Context) error {
+		err := adp.Rollback(ctx)
+		callbacks.fire(false)
+		return err
+	})
+}
 
-				switch e := p.(type) {
-				case runtime.Error:
-					panic(e)
-				case error:
-					err = e
-				default:
-					panic(e)
-				}
-			} else if err != nil {
-				_ = cw.adapter.Rollback(cw.ctx)
-			} else {
-				err = cw.adapter.Commit(cw.ctx)
-			}
-		}()
+// savepoint runs nested transaction using savepoint, so failure inside inner transaction can be recovered
+// without rolling back the outer transaction.
+func (r repository) savepoint(cw contextWrapper, fn func(cw contextWrapper) error) error {
+	var (
+		name = "rel_sp_" + strconv.Itoa(cw.depth)
+	)
+
+	if err := cw.adapter.Savepoint(cw.ctx, name); err != nil {
+		return err
+	}
+
+	var (
+		commitMark, rollbackMark = cw.callbacks.mark()
+	)
+
+	cw = wrapContext(cw.ctx, contextData{adapter: cw.adapter, depth: cw.depth + 1, options: cw.options, callbacks: cw.callbacks})
 
-		err = fn(cw)
+	return runTransaction(cw, fn, func(ctx context.Context) error {
+		return cw.adapter.Release(ctx, name)
+	}, func(ctx context.Context) error {
+		err := cw.adapter.RollbackTo(ctx, name)
+		cw.callbacks.rollbackTo(commitMark, rollbackMark)
+		return err
+	})
+}
+
+func runTransaction(cw contextWrapper, fn func(cw contextWrapper) error, commit func(ctx context.Context) error, rollback func(ctx context.Context) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollback(cw.ctx)
+
+			switch e := p.(type) {
+			case runtime.Error:
+				panic(e)
+			case error:
+				err = e
+			default:
+				panic(e)
+			}
+		} else if err != nil {
+			_ = rollback(cw.ctx)
+		} else {
+			err = commit(cw.ctx)
+		}
 	}()
 
+	err = fn(cw)
+
 	return err
 }
 
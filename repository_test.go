@@ -466,6 +466,33 @@ func TestRepository_FindAll_softDelete(t *testing.T) {
 	cur.AssertExpectations(t)
 }
 
+func TestRepository_FindAll_softDeleteCombination(t *testing.T) {
+	var (
+		addresses []Address
+		adapter   = &testAdapter{}
+		repo      = New(adapter)
+		query     = From("user_addresses").Where(Eq("user_id", 1)).
+				Union(Where(Eq("user_id", 2))).
+				Union(From("user_addresses").Where(Eq("user_id", 3)).Unscoped()).
+				UnionAll(From("archived_addresses")).
+				Limit(2)
+		cur = createCursor(2)
+	)
+
+	adapter.On("Query", From("user_addresses").Where(Eq("user_id", 1), Nil("deleted_at")).
+		Union(From("user_addresses").Where(Eq("user_id", 2), Nil("deleted_at"))).
+		Union(From("user_addresses").Where(Eq("user_id", 3)).Unscoped()).
+		UnionAll(From("archived_addresses")).
+		Limit(2)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &addresses, query))
+	assert.Len(t, addresses, 2)
+	assert.Equal(t, Where(Eq("user_id", 2)), query.CombinationQuery[0].Query)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
 func TestRepository_FindAll_softDeleteUnscoped(t *testing.T) {
 	var (
 		addresses []Address
@@ -748,6 +775,105 @@ func TestRepository_MustFindAndCountAll(t *testing.T) {
 	cur.AssertExpectations(t)
 }
 
+func TestRepository_FindPage(t *testing.T) {
+	var (
+		users   []User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		cursor  = createCursor(3)
+	)
+
+	adapter.On("Query", From("users").SortAsc("id").Limit(3)).Return(cursor, nil).Once()
+
+	page, err := repo.FindPage(context.TODO(), &users, PageSize(2))
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "", page.Prev)
+	assert.NotEqual(t, "", page.Next)
+
+	values, err := decodePageCursor(page.Next, []SortQuery{SortAsc("id")}, NewCollection(&users).Meta())
+	assert.Nil(t, err)
+	assert.Equal(t, []any{10}, values)
+
+	adapter.AssertExpectations(t)
+	cursor.AssertExpectations(t)
+}
+
+func TestRepository_FindPage_after(t *testing.T) {
+	var (
+		users    []User
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		cursor   = createCursor(1)
+		token, _ = encodePageCursor(NewDocument(&User{ID: 10}), []SortQuery{SortAsc("id")})
+		query    = From("users").Where(Gt("id", 10)).SortAsc("id").Limit(21)
+	)
+
+	adapter.On("Query", query).Return(cursor, nil).Once()
+
+	page, err := repo.FindPage(context.TODO(), &users, After(token))
+	assert.Nil(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, token, page.Prev)
+	assert.Equal(t, "", page.Next)
+
+	adapter.AssertExpectations(t)
+	cursor.AssertExpectations(t)
+}
+
+func TestRepository_FindPage_before(t *testing.T) {
+	var (
+		users    []User
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		cursor   = createCursor(3)
+		sorts    = []SortQuery{SortDesc("name"), SortAsc("id")}
+		token, _ = encodePageCursor(NewDocument(&User{ID: 10, Name: "alice"}), sorts)
+		query    = From("users").Where(Or(Gt("name", "alice"), And(Eq("name", "alice"), Lt("id", 10)))).SortAsc("name").SortDesc("id").Limit(3)
+	)
+
+	adapter.On("Query", query).Return(cursor, nil).Once()
+
+	page, err := repo.FindPage(context.TODO(), &users, SortDesc("name"), Before(token), PageSize(2))
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.NotEqual(t, "", page.Prev)
+	assert.NotEqual(t, "", page.Next)
+
+	adapter.AssertExpectations(t)
+	cursor.AssertExpectations(t)
+}
+
+func TestRepository_FindPage_invalidCursor(t *testing.T) {
+	var (
+		users   []User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+	)
+
+	page, err := repo.FindPage(context.TODO(), &users, After("invalid"))
+	assert.Equal(t, ErrInvalidPageCursor, err)
+	assert.Equal(t, Page{}, page)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_FindPage_error(t *testing.T) {
+	var (
+		users   []User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		err     = errors.New("error")
+	)
+
+	adapter.On("Query", From("users").SortAsc("id").Limit(21)).Return(&testCursor{}, err).Once()
+
+	assert.Panics(t, func() {
+		repo.MustFindPage(context.TODO(), &users)
+	})
+
+	adapter.AssertExpectations(t)
+}
 func TestRepository_Insert(t *testing.T) {
 	var (
 		adapter = &testAdapter{}
@@ -843,7 +969,7 @@ func TestRepository_Insert_saveBelongsTo(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(userID, nil).Once()
 	adapter.On("Insert", From("profiles"), mock.Anything, OnConflict{}).Return(profileID, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -898,7 +1024,7 @@ func TestRepository_Insert_saveBelongsToError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(0, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
@@ -921,7 +1047,7 @@ func TestRepository_Insert_saveHasOne(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(userID, nil).Once()
 	adapter.On("Insert", From("user_addresses"), mock.Anything, OnConflict{}).Return(addressID, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -985,7 +1111,7 @@ func TestRepository_Insert_saveHasOneError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(userID, nil).Once()
 	adapter.On("Insert", From("user_addresses"), mock.Anything, OnConflict{}).Return(0, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
@@ -1007,7 +1133,7 @@ func TestRepository_Insert_saveHasMany(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(1, nil).Once()
 	adapter.On("InsertAll", From("user_roles"), mock.Anything, mock.Anything, OnConflict{}).Return([]any(nil), nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -1067,7 +1193,7 @@ func TestRepository_Insert_saveHasManyError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(1, nil).Once()
 	adapter.On("InsertAll", From("user_roles"), mock.Anything, mock.Anything, OnConflict{}).Return([]any{}, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
@@ -1137,7 +1263,7 @@ func TestRepository_Insert_customErrorNested(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Insert", From("users"), mock.Anything, OnConflict{}).Return(1, errors.New("error")).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
@@ -1609,7 +1735,7 @@ func TestRepository_Update_saveBelongsTo(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("users").Where(Eq("id", *profile.UserID)), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Update", From("profiles").Where(Eq("id", profile.ID)), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -1680,7 +1806,7 @@ func TestRepository_Update_saveBelongsToError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", queries, "id", mock.Anything).Return(0, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
@@ -1704,7 +1830,7 @@ func TestRepository_Update_saveHasOne(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("users").Where(Eq("id", 10)), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Update", From("user_addresses").Where(Eq("id", 1).AndEq("user_id", 10).AndNil("deleted_at")), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -1772,7 +1898,7 @@ func TestRepository_Update_saveHasOneError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("users").Where(Eq("id", 10)), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Update", From("user_addresses").Where(Eq("id", 1).AndEq("user_id", 10).AndNil("deleted_at")), "id", mock.Anything).Return(1, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
@@ -1793,7 +1919,7 @@ func TestRepository_Update_saveHasMany(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("users").Where(Eq("id", 10)), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Delete", From("user_roles").Where(Eq("user_id", 10))).Return(1, nil).Once()
 	adapter.On("InsertAll", From("user_roles"), mock.Anything, mock.Anything, OnConflict{}).Return([]any(nil), nil).Once()
@@ -1852,7 +1978,7 @@ func TestRepository_Update_saveHasManyError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("users").Where(Eq("id", 10)), "id", mock.Anything).Return(1, nil).Once()
 	adapter.On("Delete", From("user_roles").Where(Eq("user_id", 10))).Return(0, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
@@ -1911,7 +2037,7 @@ func TestRepository_Update_saveHasManyForceCascade(t *testing.T) {
 
 			// forced association is replaced with an empty one,
 			// so all existing records are deleted.
-			adapter.On("Begin").Return(nil).Once()
+			adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 			adapter.On("Update", From("users").Where(Eq("id", 10)), "id", mock.Anything).Return(1, nil).Once()
 			adapter.On("Delete", From("user_roles").Where(Eq("user_id", 10))).Return(1, nil).Once()
 			adapter.On("Commit").Return(nil).Once()
@@ -2942,7 +3068,7 @@ func TestRepository_Delete_belongsTo(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Delete", From("profiles").Where(Eq("id", profile.ID))).Return(1, nil).Once()
 	adapter.On("Delete", From("users").Where(Eq("id", *profile.UserID))).Return(1, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -2967,7 +3093,7 @@ func TestRepository_Delete_belongsToInconsistentAssoc(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Delete", From("profiles").Where(Eq("id", profile.ID))).Return(1, nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
@@ -2996,7 +3122,7 @@ func TestRepository_Delete_belongsToError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Delete", From("users").Where(Eq("id", *profile.UserID))).Return(1, err).Once()
 	adapter.On("Delete", From("profiles").Where(Eq("id", profile.ID))).Return(1, nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
@@ -3024,7 +3150,7 @@ func TestRepository_Delete_hasOne(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("user_addresses").Where(Eq("id", 1).AndEq("user_id", 10)), "", addressMut).Return(1, nil).Once()
 	adapter.On("Delete", From("users").Where(Eq("id", 10))).Return(1, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -3049,7 +3175,7 @@ func TestRepository_Delete_hasOneInconsistentAssoc(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
 	assert.Equal(t, ConstraintError{
@@ -3080,7 +3206,7 @@ func TestRepository_Delete_hasOneError(t *testing.T) {
 		err     = errors.New("error")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Update", From("user_addresses").Where(Eq("id", 1).AndEq("user_id", 10)), "", addressMut).Return(1, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
@@ -3089,6 +3215,122 @@ func TestRepository_Delete_hasOneError(t *testing.T) {
 	adapter.AssertExpectations(t)
 }
 
+func TestRepository_Insert_saveHasManyThrough(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		post    = Post{Title: "rel", Tags: []Tag{{Name: "go"}, {ID: 2, Name: "orm"}}}
+		links   = []map[string]Mutate{
+			{"post_id": Set("post_id", 1), "tag_id": Set("tag_id", 3)},
+			{"post_id": Set("post_id", 1), "tag_id": Set("tag_id", 2)},
+		}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Insert", From("posts"), map[string]Mutate{"title": Set("title", "rel")}, OnConflict{}).Return(1, nil).Once()
+	adapter.On("Insert", From("tags"), map[string]Mutate{"name": Set("name", "go")}, OnConflict{}).Return(3, nil).Once()
+	adapter.On("Update", From("tags").Where(Eq("id", 2)), "id", mock.Anything).Return(1, nil).Once()
+	adapter.On("InsertAll", From("post_tags"), mock.Anything, links, OnConflict{}).Return([]any(nil), nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Insert(context.TODO(), &post))
+	assert.Equal(t, Post{ID: 1, Title: "rel", Tags: []Tag{{ID: 3, Name: "go"}, {ID: 2, Name: "orm"}}}, post)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Update_saveHasManyThroughReplace(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		post    = Post{ID: 1, Title: "rel", Tags: []Tag{{ID: 2, Name: "orm"}}}
+		links   = []map[string]Mutate{
+			{"post_id": Set("post_id", 1), "tag_id": Set("tag_id", 2)},
+		}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Update", From("posts").Where(Eq("id", 1)), "id", mock.Anything).Return(1, nil).Once()
+	adapter.On("Update", From("tags").Where(Eq("id", 2)), "id", mock.Anything).Return(1, nil).Once()
+	adapter.On("Delete", From("post_tags").Where(Eq("post_id", 1))).Return(2, nil).Once()
+	adapter.On("InsertAll", From("post_tags"), mock.Anything, links, OnConflict{}).Return([]any(nil), nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Update(context.TODO(), &post))
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Update_saveHasManyThroughChangeset(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		post    = Post{ID: 1, Title: "rel", Tags: []Tag{{ID: 1, Name: "go"}, {ID: 2, Name: "orm"}}}
+		cur     = &testCursor{}
+		links   = []map[string]Mutate{
+			{"post_id": Set("post_id", 1), "tag_id": Set("tag_id", 3)},
+			{"post_id": Set("post_id", 1), "tag_id": Set("tag_id", 4)},
+		}
+	)
+
+	changeset := NewChangeset(&post)
+	post.Tags = []Tag{{ID: 2, Name: "orm"}, {ID: 3, Name: "sql"}, {Name: "db"}}
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Update", From("tags").Where(Eq("id", 3)), "id", mock.Anything).Return(1, nil).Once()
+	adapter.On("Insert", From("tags"), map[string]Mutate{"name": Set("name", "db")}, OnConflict{}).Return(4, nil).Once()
+	adapter.On("Delete", From("post_tags").Where(Eq("post_id", 1), In("tag_id", 1))).Return(1, nil).Once()
+	adapter.On("Query", From("post_tags").Where(Eq("post_id", 1), In("tag_id", 2, 3, 4))).Return(cur, nil).Once()
+	adapter.On("InsertAll", From("post_tags"), mock.Anything, links, OnConflict{}).Return([]any(nil), nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"post_id", "tag_id"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(1, 2).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Update(context.TODO(), &post, changeset))
+	assert.Equal(t, []Tag{{ID: 2, Name: "orm"}, {ID: 3, Name: "sql"}, {ID: 4, Name: "db"}}, post.Tags)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Update_saveHasManyThroughUnchanged(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		post    = Post{ID: 1, Title: "rel", Tags: []Tag{{ID: 1, Name: "go"}}}
+	)
+
+	changeset := NewChangeset(&post)
+	post.Title = "go-rel"
+
+	adapter.On("Update", From("posts").Where(Eq("id", 1)), "id", map[string]Mutate{"title": Set("title", "go-rel")}).Return(1, nil).Once()
+
+	assert.Nil(t, repo.Update(context.TODO(), &post, changeset))
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Delete_hasManyThrough(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		post    = Post{ID: 1, Tags: []Tag{{ID: 2}, {ID: 3}}}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Delete", From("post_tags").Where(Eq("post_id", 1))).Return(2, nil).Once()
+	adapter.On("Delete", From("posts").Where(Eq("id", 1))).Return(1, nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Delete(context.TODO(), &post, Cascade(true)))
+
+	adapter.AssertExpectations(t)
+}
+
 func TestRepository_Delete_hasMany(t *testing.T) {
 	var (
 		user = User{
@@ -3101,7 +3343,7 @@ func TestRepository_Delete_hasMany(t *testing.T) {
 		repo    = New(adapter)
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Delete", From("user_roles").Where(Eq("user_id", 10).And(Or(Eq("user_id", 10).AndEq("role_id", 1))))).Return(1, nil).Once()
 	adapter.On("Delete", From("users").Where(Eq("id", 10))).Return(1, nil).Once()
 	adapter.On("Commit").Return(nil).Once()
@@ -3124,7 +3366,7 @@ func TestRepository_Delete_hasManyError(t *testing.T) {
 		err     = errors.New("err")
 	)
 
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Delete", From("user_roles").Where(Eq("user_id", 10).And(Or(Eq("user_id", 10).AndEq("role_id", 1))))).Return(1, err).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
@@ -3631,6 +3873,112 @@ func TestRepository_Preload_hasMany(t *testing.T) {
 	cur.AssertExpectations(t)
 }
 
+func TestRepository_Preload_hasManyAssocFilter(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = User{ID: 10}
+		cur     = &testCursor{}
+	)
+
+	adapter.On("Query", From("transactions").Where(
+		Exists(From("histories as histories").Where(Eq("histories.transaction_id", Field("transactions.id")))),
+		In("user_id", 10),
+	)).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "user_id"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(5, 10).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(context.TODO(), &user, "transactions", HasAssoc("histories")))
+	assert.Equal(t, []Transaction{{ID: 5, BuyerID: 10}}, user.Transactions)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+type testPartitionLimitAdapter struct {
+	*testAdapter
+}
+
+func (testPartitionLimitAdapter) SupportPartitionLimit() bool {
+	return true
+}
+
+func TestRepository_Preload_hasManyLimit(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(testPartitionLimitAdapter{adapter})
+		user    = User{ID: 10}
+		cur     = &testCursor{}
+	)
+
+	adapter.On("Query", From("transactions").Where(In("user_id", 10)).SortDesc("id").PartitionLimit("user_id", 2)).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "user_id"}, nil).Once()
+	cur.On("Next").Return(true).Twice()
+	cur.MockScan(15, 10).Once()
+	cur.MockScan(5, 10).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(context.TODO(), &user, "transactions", NewSortDesc("id"), PreloadLimit(2)))
+	assert.Equal(t, []Transaction{{ID: 15, BuyerID: 10}, {ID: 5, BuyerID: 10}}, user.Transactions)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Preload_hasManyLimitFallback(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		users   = []User{{ID: 10}, {ID: 20}}
+		cur1    = &testCursor{}
+		cur2    = &testCursor{}
+	)
+
+	adapter.On("Query", From("transactions").Where(In("user_id", 10)).SortDesc("id").Limit(1)).Return(cur1, nil).Once()
+	adapter.On("Query", From("transactions").Where(In("user_id", 20)).SortDesc("id").Limit(1)).Return(cur2, nil).Once()
+
+	cur1.On("Close").Return(nil).Once()
+	cur1.On("Fields").Return([]string{"id", "user_id"}, nil).Once()
+	cur1.On("Next").Return(true).Once()
+	cur1.MockScan(15, 10).Once()
+	cur1.On("Next").Return(false).Once()
+
+	cur2.On("Close").Return(nil).Once()
+	cur2.On("Fields").Return([]string{"id", "user_id"}, nil).Once()
+	cur2.On("Next").Return(true).Once()
+	cur2.MockScan(25, 20).Once()
+	cur2.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(context.TODO(), &users, "transactions", NewSortDesc("id"), PreloadLimit(1)))
+	assert.Equal(t, []Transaction{{ID: 15, BuyerID: 10}}, users[0].Transactions)
+	assert.Equal(t, []Transaction{{ID: 25, BuyerID: 20}}, users[1].Transactions)
+
+	adapter.AssertExpectations(t)
+	cur1.AssertExpectations(t)
+	cur2.AssertExpectations(t)
+}
+
+func TestRepository_Preload_hasManyLimitFallback_error(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		user    = User{ID: 10}
+		err     = errors.New("error")
+	)
+
+	adapter.On("Query", From("transactions").Where(In("user_id", 10)).Limit(2)).Return(&testCursor{}, err).Once()
+
+	assert.Equal(t, err, repo.Preload(context.TODO(), &user, "transactions", PreloadLimit(2)))
+
+	adapter.AssertExpectations(t)
+}
+
 func TestRepository_Preload_sliceHasMany(t *testing.T) {
 	var (
 		adapter      = &testAdapter{}
@@ -4199,6 +4547,74 @@ func TestRepository_MustPreload(t *testing.T) {
 	cur.AssertExpectations(t)
 }
 
+type Book struct {
+	ID        int
+	AuthorID  int
+	DeletedAt *time.Time
+}
+
+type Author struct {
+	ID         int
+	Books      []Book
+	BooksCount int `db:"books_count,count:books"`
+}
+
+func TestRepository_PreloadCount(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		authors = []Author{{ID: 1}, {ID: 2, BooksCount: 5}, {ID: 1}, {}}
+		cur     = &testCursor{}
+		query   = From("books").
+			Where(Eq("id", 3), In("author_id", 1, 2), Nil("deleted_at")).
+			Select("author_id").
+			SelectExpr(Fn("count", Field("*")).As("count")).
+			Group("author_id")
+	)
+
+	adapter.On("Query", query).Return(cur, nil).Once()
+	assert.Equal(t, SelectQuery{Fields: []string{"author_id"}, Expressions: []SelectExpression{Fn("count", Field("*")).As("count")}}, query.SelectQuery)
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"author_id", "count"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(1, 3).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.PreloadCount(context.TODO(), &authors, "books", Eq("id", 3), NewSortAsc("id"), Limit(1)))
+	assert.Equal(t, []Author{{ID: 1, BooksCount: 3}, {ID: 2}, {ID: 1, BooksCount: 3}, {}}, authors)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_PreloadCount_error(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		author  = Author{ID: 1}
+		err     = errors.New("error")
+	)
+
+	adapter.On("Query", mock.Anything).Return(&testCursor{}, err).Once()
+
+	assert.Equal(t, err, repo.PreloadCount(context.TODO(), &author, "books"))
+	assert.Panics(t, func() { repo.MustPreloadCount(context.TODO(), &author, "books") })
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_PreloadCount_invalid(t *testing.T) {
+	var (
+		repo = New(&testAdapter{})
+		user = User{ID: 1}
+	)
+
+	assert.PanicsWithValue(t, "rel: count field of association transactions is not defined", func() {
+		_ = repo.PreloadCount(context.TODO(), &user, "transactions")
+	})
+}
+
 func TestRepository_Exec(t *testing.T) {
 	var (
 		adapter = &testAdapter{}
@@ -4240,7 +4656,7 @@ func TestRepository_MustExec(t *testing.T) {
 
 func TestRepository_Transaction(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(nil).On("Commit").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).On("Commit").Return(nil).Once()
 
 	repo := New(adapter)
 
@@ -4255,7 +4671,7 @@ func TestRepository_Transaction(t *testing.T) {
 
 func TestRepository_Transaction_beginError(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(errors.New("error")).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(errors.New("error")).Once()
 
 	err := New(adapter).Transaction(context.TODO(), func(ctx context.Context) error {
 		// doing good things
@@ -4268,7 +4684,7 @@ func TestRepository_Transaction_beginError(t *testing.T) {
 
 func TestRepository_Transaction_commitError(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Commit").Return(errors.New("error")).Once()
 
 	err := New(adapter).Transaction(context.TODO(), func(ctx context.Context) error {
@@ -4282,7 +4698,7 @@ func TestRepository_Transaction_commitError(t *testing.T) {
 
 func TestRepository_Transaction_returnErrorAndRollback(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
 	err := New(adapter).Transaction(context.TODO(), func(ctx context.Context) error {
@@ -4296,7 +4712,7 @@ func TestRepository_Transaction_returnErrorAndRollback(t *testing.T) {
 
 func TestRepository_Transaction_panicWithErrorAndRollback(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
 	err := New(adapter).Transaction(context.TODO(), func(ctx context.Context) error {
@@ -4310,7 +4726,7 @@ func TestRepository_Transaction_panicWithErrorAndRollback(t *testing.T) {
 
 func TestRepository_Transaction_panicWithStringAndRollback(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
 	assert.Panics(t, func() {
@@ -4325,7 +4741,7 @@ func TestRepository_Transaction_panicWithStringAndRollback(t *testing.T) {
 
 func TestRepository_Transaction_runtimeError(t *testing.T) {
 	adapter := &testAdapter{}
-	adapter.On("Begin").Return(nil).Once()
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
 	adapter.On("Rollback").Return(nil).Once()
 
 	var user *User
@@ -4338,3 +4754,391 @@ func TestRepository_Transaction_runtimeError(t *testing.T) {
 
 	adapter.AssertExpectations(t)
 }
+
+func TestRepository_Transaction_nested(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_2").Return(nil).Once()
+	adapter.On("Release", "rel_sp_2").Return(nil).Once()
+	adapter.On("Release", "rel_sp_1").Return(nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	repo := New(adapter)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			return repo.Transaction(ctx, func(ctx context.Context) error {
+				return nil
+			})
+		})
+	})
+
+	assert.Nil(t, err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_nestedRecovered(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("RollbackTo", "rel_sp_1").Return(nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	repo := New(adapter)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		err := repo.Transaction(ctx, func(ctx context.Context) error {
+			return errors.New("error")
+		})
+
+		assert.Equal(t, errors.New("error"), err)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_nestedPanicRecovered(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("RollbackTo", "rel_sp_1").Return(nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	repo := New(adapter)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		err := repo.Transaction(ctx, func(ctx context.Context) error {
+			panic(errors.New("error"))
+		})
+
+		assert.Equal(t, errors.New("error"), err)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_savepointError(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(errors.New("error")).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	repo := New(adapter)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	assert.Equal(t, errors.New("error"), err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_releaseError(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("Release", "rel_sp_1").Return(errors.New("error")).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	repo := New(adapter)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	assert.Equal(t, errors.New("error"), err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_options(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		options = TransactionOptions{Isolation: Serializable, ReadOnly: true}
+	)
+
+	adapter.On("Begin", options).Return(nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	err := New(adapter).Transaction(context.TODO(), func(ctx context.Context) error {
+		return nil
+	}, Isolation(Serializable), ReadOnly())
+
+	assert.Nil(t, err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_nestedIncompatibleOptions(t *testing.T) {
+	adapter := &testAdapter{}
+	adapter.On("Begin", TransactionOptions{Isolation: ReadCommitted}).Return(nil).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	repo := New(adapter)
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			return nil
+		}, Isolation(Serializable))
+	}, Isolation(ReadCommitted))
+
+	assert.Equal(t, ErrIncompatibleTransaction, err)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_retry(t *testing.T) {
+	var (
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		attempts = 0
+		retried  = 0
+	)
+
+	repo.Instrumentation(func(ctx context.Context, op string, message string, args ...any) func(err error) {
+		if op == "rel-transaction-retry" {
+			retried++
+		}
+		return func(err error) {}
+	})
+
+	adapter.On("Begin", TransactionOptions{Isolation: Serializable}).Return(nil).Times(3)
+	adapter.On("Rollback").Return(nil).Twice()
+	adapter.On("Commit").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return TransactionError{Type: SerializationFailure}
+		}
+
+		return nil
+	}, Isolation(Serializable), Retry(3, time.Millisecond))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 2, retried)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_retryExhausted(t *testing.T) {
+	var (
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		attempts = 0
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Twice()
+	adapter.On("Rollback").Return(nil).Twice()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		return ErrDeadlock
+	}, RetryPolicy{MaxAttempts: 2})
+
+	assert.Equal(t, ErrDeadlock, err)
+	assert.Equal(t, 2, attempts)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_retryNonRetryableError(t *testing.T) {
+	var (
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		attempts = 0
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("error")
+	}, Retry(3, time.Millisecond))
+
+	assert.Equal(t, errors.New("error"), err)
+	assert.Equal(t, 1, attempts)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_retryContextCanceled(t *testing.T) {
+	var (
+		adapter     = &testAdapter{}
+		repo        = New(adapter)
+		ctx, cancel = context.WithCancel(context.TODO())
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	err := repo.Transaction(ctx, func(ctx context.Context) error {
+		cancel()
+		return ErrSerialization
+	}, Retry(3, time.Hour))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, ErrSerialization)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_retryOnlyOutermost(t *testing.T) {
+	var (
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		attempts = 0
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Twice()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Twice()
+	adapter.On("RollbackTo", "rel_sp_1").Return(nil).Twice()
+	adapter.On("Rollback").Return(nil).Twice()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		return repo.Transaction(ctx, func(ctx context.Context) error {
+			attempts++
+			return ErrDeadlock
+		}, Retry(5, 0))
+	}, Retry(2, 0))
+
+	assert.Equal(t, ErrDeadlock, err)
+	assert.Equal(t, 2, attempts)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_afterCommit(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		calls   []string
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("Release", "rel_sp_1").Return(nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "commit 1") })
+		AfterRollback(ctx, func() { calls = append(calls, "rollback 1") })
+
+		err := repo.Transaction(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { calls = append(calls, "commit 2") })
+			return nil
+		})
+
+		assert.Nil(t, calls)
+		return err
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"commit 1", "commit 2"}, calls)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_afterRollback(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		calls   []string
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "commit 1") })
+		AfterRollback(ctx, func() { calls = append(calls, "rollback 1") })
+		AfterRollback(ctx, func() { calls = append(calls, "rollback 2") })
+		return errors.New("error")
+	})
+
+	assert.Equal(t, errors.New("error"), err)
+	assert.Equal(t, []string{"rollback 1", "rollback 2"}, calls)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_afterRollbackCommitError(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		calls   []string
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Commit").Return(errors.New("error")).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "commit") })
+		AfterRollback(ctx, func() { calls = append(calls, "rollback") })
+		return nil
+	})
+
+	assert.Equal(t, errors.New("error"), err)
+	assert.Equal(t, []string{"rollback"}, calls)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_afterCommitNestedRollback(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		calls   []string
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("RollbackTo", "rel_sp_1").Return(nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { calls = append(calls, "commit 1") })
+
+		_ = repo.Transaction(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { calls = append(calls, "commit 2") })
+			AfterRollback(ctx, func() { calls = append(calls, "rollback 2") })
+			return errors.New("error")
+		})
+
+		assert.Empty(t, calls)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"commit 1"}, calls)
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_afterRollbackNestedRollback(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		calls   []string
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Savepoint", "rel_sp_1").Return(nil).Once()
+	adapter.On("RollbackTo", "rel_sp_1").Return(nil).Once()
+	adapter.On("Rollback").Return(nil).Once()
+
+	err := repo.Transaction(context.TODO(), func(ctx context.Context) error {
+		AfterRollback(ctx, func() { calls = append(calls, "rollback 1") })
+
+		_ = repo.Transaction(ctx, func(ctx context.Context) error {
+			AfterRollback(ctx, func() { calls = append(calls, "rollback 2") })
+			return errors.New("error")
+		})
+
+		assert.Empty(t, calls)
+		return errors.New("error")
+	})
+
+	assert.Equal(t, errors.New("error"), err)
+	assert.Equal(t, []string{"rollback 1"}, calls)
+	adapter.AssertExpectations(t)
+}
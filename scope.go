@@ -0,0 +1,60 @@
+package rel
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+var (
+	defaultScopes  sync.Map
+	rtDefaultScope = reflect.TypeOf((*DefaultScope)(nil)).Elem()
+)
+
+// DefaultScope is implemented by entity that needs to be scoped on every query, such as archived or tenant filter.
+// The returned queriers are merged into find, count, aggregate, update any and preload query of the entity's table,
+// use Unscoped(true) to bypass it. The method is called on zero value of the entity, so it shouldn't depend on its fields.
+//
+// Entity is registered once it's used by rel, table only operations such as Count, Aggregate and UpdateAny
+// are only scoped after the entity is used or registered using RegisterDefaultScope.
+type DefaultScope interface {
+	DefaultScope(ctx context.Context) []Querier
+}
+
+// RegisterDefaultScope registers default scope of the entities by their table name.
+// Table only operations such as Count, Aggregate and UpdateAny only know the table name,
+// register the entities before executing any query, eg: in init function, so they are scoped regardless of the order of queries.
+func RegisterDefaultScope(entities ...DefaultScope) {
+	for _, entity := range entities {
+		defaultScopes.Store(tableName(indirectReflectType(reflect.TypeOf(entity))), entity)
+	}
+}
+
+// registerDefaultScope stores default scope of the type if it implements DefaultScope,
+// scope registered using RegisterDefaultScope is kept.
+func registerDefaultScope(rt reflect.Type, table string) {
+	switch {
+	case rt.Implements(rtDefaultScope):
+		defaultScopes.LoadOrStore(table, reflect.Zero(rt).Interface().(DefaultScope))
+	case reflect.PtrTo(rt).Implements(rtDefaultScope):
+		defaultScopes.LoadOrStore(table, reflect.New(rt).Interface().(DefaultScope))
+	}
+}
+
+// withEntityScope merges default scope registered for the table into the query.
+func withEntityScope(ctx context.Context, table string, query Query) Query {
+	if query.UnscopedQuery {
+		return query
+	}
+
+	scope, ok := defaultScopes.Load(table)
+	if !ok {
+		return query
+	}
+
+	for _, querier := range scope.(DefaultScope).DefaultScope(ctx) {
+		querier.Build(&query)
+	}
+
+	return query
+}
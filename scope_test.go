@@ -0,0 +1,176 @@
+package rel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+type ScopedNote struct {
+	ID       int
+	AuthorID int
+	TenantID int
+	Archived bool
+}
+
+func (ScopedNote) DefaultScope(ctx context.Context) []Querier {
+	queriers := []Querier{Eq("archived", false)}
+	if tenant, ok := ctx.Value(tenantKey{}).(int); ok {
+		queriers = append(queriers, Eq("tenant_id", tenant))
+	}
+
+	return queriers
+}
+
+type ScopedAuthor struct {
+	ID    int
+	Notes []ScopedNote `ref:"id" fk:"author_id"`
+}
+
+type ScopedDraft struct {
+	ID        int
+	Published bool
+}
+
+func (*ScopedDraft) DefaultScope(ctx context.Context) []Querier {
+	return []Querier{Eq("published", false)}
+}
+
+func TestRegisterDefaultScope(t *testing.T) {
+	RegisterDefaultScope(&ScopedDraft{})
+
+	scope, ok := defaultScopes.Load("scoped_drafts")
+	assert.True(t, ok)
+	assert.IsType(t, &ScopedDraft{}, scope)
+
+	_, ok = defaultScopes.Load("users")
+	assert.False(t, ok)
+}
+
+func TestDefaultScope_used(t *testing.T) {
+	type UsedDraft struct {
+		ScopedDraft
+	}
+
+	NewDocument(&UsedDraft{})
+
+	scope, ok := defaultScopes.Load("used_drafts")
+	assert.True(t, ok)
+	assert.IsType(t, &UsedDraft{}, scope)
+}
+
+func TestWithEntityScope(t *testing.T) {
+	RegisterDefaultScope(ScopedNote{})
+
+	var (
+		ctx   = context.WithValue(context.TODO(), tenantKey{}, 1)
+		query = From("scoped_notes").Where(Eq("id", 1))
+	)
+
+	assert.Equal(t, query.Where(Eq("archived", false), Eq("tenant_id", 1)), withEntityScope(ctx, "scoped_notes", query))
+	assert.Equal(t, query.Unscoped(), withEntityScope(ctx, "scoped_notes", query.Unscoped()))
+	assert.Equal(t, From("users"), withEntityScope(ctx, "users", From("users")))
+}
+
+func TestRepository_Find_defaultScope(t *testing.T) {
+	var (
+		note    ScopedNote
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = context.WithValue(context.TODO(), tenantKey{}, 1)
+		cur     = createCursor(1)
+	)
+
+	adapter.On("Query", From("scoped_notes").Where(Eq("id", 10), Eq("archived", false), Eq("tenant_id", 1)).Limit(1)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.Find(ctx, &note, Eq("id", 10)))
+	assert.Equal(t, 10, note.ID)
+	assert.False(t, cur.Next())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_FindAll_defaultScopeUnscoped(t *testing.T) {
+	var (
+		notes   []ScopedNote
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		cur     = createCursor(1)
+	)
+
+	adapter.On("Query", From("scoped_notes").Unscoped()).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &notes, Unscoped(true)))
+	assert.Len(t, notes, 1)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Count_defaultScope(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+	)
+
+	RegisterDefaultScope(ScopedNote{})
+	adapter.On("Aggregate", From("scoped_notes").Where(Eq("author_id", 1), Eq("archived", false)), "count", "*").Return(1, nil).Once()
+	adapter.On("Aggregate", From("scoped_notes").Where(Eq("author_id", 1)).Unscoped(), "sum", "id").Return(3, nil).Once()
+
+	count, err := repo.Count(context.TODO(), "scoped_notes", Eq("author_id", 1))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	sum, err := repo.Aggregate(context.TODO(), From("scoped_notes").Where(Eq("author_id", 1)).Unscoped(), "sum", "id")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, sum)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_UpdateAny_defaultScope(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = context.WithValue(context.TODO(), tenantKey{}, 2)
+		mutates = map[string]Mutate{
+			"archived": Set("archived", true),
+		}
+	)
+
+	RegisterDefaultScope(ScopedNote{})
+	adapter.On("Update", From("scoped_notes").Where(Eq("author_id", 1), Eq("archived", false), Eq("tenant_id", 2)), "", mutates).Return(1, nil).Once()
+
+	updatedCount, err := repo.UpdateAny(ctx, From("scoped_notes").Where(Eq("author_id", 1)), Set("archived", true))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updatedCount)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Preload_defaultScope(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		author  = ScopedAuthor{ID: 10}
+		cur     = &testCursor{}
+	)
+
+	adapter.On("Query", From("scoped_notes").Where(In("author_id", 10), Eq("archived", false))).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "author_id"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(5, 10).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(context.TODO(), &author, "notes"))
+	assert.Equal(t, []ScopedNote{{ID: 5, AuthorID: 10}}, author.Notes)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
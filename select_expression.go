@@ -0,0 +1,290 @@
+package rel
+
+import (
+	"strings"
+)
+
+// SelectExpression defines computed select expression, the result is returned as a column named by Alias.
+// The result can be scanned into struct field that has the same name as Alias,
+// use `db:"name,computed"` tag to mark the field as not persisted.
+type SelectExpression struct {
+	Expr  Expr
+	Alias string
+}
+
+// String representation.
+func (se SelectExpression) String() string {
+	return se.Expr.String() + ".As(\"" + se.Alias + "\")"
+}
+
+// Expr is a structured expression that can be rendered by adapter.
+// It's one of FieldExpr, ValueExpr, FuncExpr, WindowExpr, CaseExpr or MatchExpr.
+type Expr interface {
+	String() string
+	expr()
+}
+
+// FieldExpr refers to a field of the table.
+type FieldExpr string
+
+func (FieldExpr) expr() {}
+
+// As alias the expression.
+func (fe FieldExpr) As(alias string) SelectExpression {
+	return SelectExpression{Expr: fe, Alias: alias}
+}
+
+// String representation.
+func (fe FieldExpr) String() string {
+	return "rel.Field(\"" + string(fe) + "\")"
+}
+
+// Field expression.
+func Field(name string) FieldExpr {
+	return FieldExpr(name)
+}
+
+// ValueExpr is a literal value, adapter should pass it as query argument.
+type ValueExpr struct {
+	Value any
+}
+
+func (ValueExpr) expr() {}
+
+// As alias the expression.
+func (ve ValueExpr) As(alias string) SelectExpression {
+	return SelectExpression{Expr: ve, Alias: alias}
+}
+
+// String representation.
+func (ve ValueExpr) String() string {
+	return "rel.Value(" + fmtAny(ve.Value) + ")"
+}
+
+// Value expression.
+func Value(value any) ValueExpr {
+	return ValueExpr{Value: value}
+}
+
+// FuncExpr calls a database function such as count, sum or row_number.
+type FuncExpr struct {
+	Name         string
+	OnlyDistinct bool
+	Args         []Expr
+}
+
+func (FuncExpr) expr() {}
+
+// Distinct applies distinct to the arguments of aggregate function.
+func (fe FuncExpr) Distinct() FuncExpr {
+	fe.OnlyDistinct = true
+	return fe
+}
+
+// Over evaluates the function as window function.
+func (fe FuncExpr) Over() WindowExpr {
+	return WindowExpr{Func: fe}
+}
+
+// As alias the expression.
+func (fe FuncExpr) As(alias string) SelectExpression {
+	return SelectExpression{Expr: fe, Alias: alias}
+}
+
+// String representation.
+func (fe FuncExpr) String() string {
+	var builder strings.Builder
+	builder.WriteString("rel.Fn(\"")
+	builder.WriteString(fe.Name)
+	builder.WriteByte('"')
+
+	for i := range fe.Args {
+		builder.WriteString(", ")
+		builder.WriteString(fe.Args[i].String())
+	}
+
+	builder.WriteByte(')')
+
+	if fe.OnlyDistinct {
+		builder.WriteString(".Distinct()")
+	}
+
+	return builder.String()
+}
+
+// Fn create a function call expression.
+func Fn(name string, args ...Expr) FuncExpr {
+	return FuncExpr{
+		Name: name,
+		Args: args,
+	}
+}
+
+// WindowExpr evaluates function over a window of rows: fn OVER (PARTITION BY ... ORDER BY ...).
+type WindowExpr struct {
+	Func      FuncExpr
+	Partition []string
+	SortQuery []SortQuery
+}
+
+func (WindowExpr) expr() {}
+
+// PartitionBy divides rows into partitions, the function is evaluated separately for each partition.
+func (we WindowExpr) PartitionBy(fields ...string) WindowExpr {
+	we.Partition = append(we.Partition, fields...)
+	return we
+}
+
+// OrderBy sorts rows within the partition.
+func (we WindowExpr) OrderBy(sorts ...SortQuery) WindowExpr {
+	we.SortQuery = append(we.SortQuery, sorts...)
+	return we
+}
+
+// As alias the expression.
+func (we WindowExpr) As(alias string) SelectExpression {
+	return SelectExpression{Expr: we, Alias: alias}
+}
+
+// String representation.
+func (we WindowExpr) String() string {
+	var builder strings.Builder
+	builder.WriteString(we.Func.String())
+	builder.WriteString(".Over()")
+
+	if len(we.Partition) > 0 {
+		builder.WriteString(".PartitionBy(\"")
+		builder.WriteString(strings.Join(we.Partition, "\", \""))
+		builder.WriteString("\")")
+	}
+
+	if len(we.SortQuery) > 0 {
+		builder.WriteString(".OrderBy(")
+		for i, sq := range we.SortQuery {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+
+			if sq.Asc() {
+				builder.WriteString("rel.SortAsc(")
+			} else {
+				builder.WriteString("rel.SortDesc(")
+			}
+
+			if sq.Expr != nil {
+				builder.WriteString(sq.Expr.String())
+			} else {
+				builder.WriteByte('"')
+				builder.WriteString(sq.Field)
+				builder.WriteByte('"')
+			}
+			builder.WriteByte(')')
+		}
+		builder.WriteByte(')')
+	}
+
+	return builder.String()
+}
+
+// WhenExpr is a branch of case expression.
+type WhenExpr struct {
+	Filter FilterQuery
+	Then   Expr
+}
+
+// When create a case branch that returns then when filter matches.
+func When(filter FilterQuery, then Expr) WhenExpr {
+	return WhenExpr{
+		Filter: filter,
+		Then:   then,
+	}
+}
+
+// CaseExpr returns result of the first branch that matches: CASE WHEN ... THEN ... ELSE ... END.
+type CaseExpr struct {
+	Whens   []WhenExpr
+	Default Expr
+}
+
+func (CaseExpr) expr() {}
+
+// Else sets the result when no branch matches, defaults to NULL.
+func (ce CaseExpr) Else(expr Expr) CaseExpr {
+	ce.Default = expr
+	return ce
+}
+
+// As alias the expression.
+func (ce CaseExpr) As(alias string) SelectExpression {
+	return SelectExpression{Expr: ce, Alias: alias}
+}
+
+// String representation.
+func (ce CaseExpr) String() string {
+	var builder strings.Builder
+	builder.WriteString("rel.Case(")
+
+	for i, we := range ce.Whens {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+
+		builder.WriteString("rel.When(")
+		builder.WriteString(we.Filter.String())
+		builder.WriteString(", ")
+		builder.WriteString(we.Then.String())
+		builder.WriteByte(')')
+	}
+
+	builder.WriteByte(')')
+
+	if ce.Default != nil {
+		builder.WriteString(".Else(")
+		builder.WriteString(ce.Default.String())
+		builder.WriteByte(')')
+	}
+
+	return builder.String()
+}
+
+// MatchExpr is full text search relevance of query against fields, higher value is more relevant.
+// Adapter translates it to the native full text feature, such as ts_rank in postgres or MATCH ... AGAINST in mysql.
+type MatchExpr struct {
+	Fields []string
+	Query  string
+}
+
+func (MatchExpr) expr() {}
+
+// As alias the expression.
+func (me MatchExpr) As(alias string) SelectExpression {
+	return SelectExpression{Expr: me, Alias: alias}
+}
+
+// String representation.
+func (me MatchExpr) String() string {
+	return "rel.Rank(" + fmtFields(me.Fields) + ", \"" + me.Query + "\")"
+}
+
+// Rank create full text search relevance expression.
+func Rank(fields []string, query string) MatchExpr {
+	return MatchExpr{
+		Fields: fields,
+		Query:  query,
+	}
+}
+
+func fmtFields(fields []string) string {
+	if len(fields) == 0 {
+		return "[]string{}"
+	}
+
+	return "[]string{\"" + strings.Join(fields, "\", \"") + "\"}"
+}
+
+// Case create a case expression.
+func Case(whens ...WhenExpr) CaseExpr {
+	return CaseExpr{
+		Whens: whens,
+	}
+}
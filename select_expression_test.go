@@ -0,0 +1,80 @@
+package rel_test
+
+import (
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectExpression_String(t *testing.T) {
+	tests := []struct {
+		result string
+		expr   rel.SelectExpression
+	}{
+		{
+			result: "rel.Field(\"name\").As(\"n\")",
+			expr:   rel.Field("name").As("n"),
+		},
+		{
+			result: "rel.Value(\"active\").As(\"status\")",
+			expr:   rel.Value("active").As("status"),
+		},
+		{
+			result: "rel.Fn(\"count\", rel.Field(\"*\")).As(\"n\")",
+			expr:   rel.Fn("count", rel.Field("*")).As("n"),
+		},
+		{
+			result: "rel.Fn(\"count\", rel.Field(\"name\")).Distinct().As(\"n\")",
+			expr:   rel.Fn("count", rel.Field("name")).Distinct().As("n"),
+		},
+		{
+			result: "rel.Fn(\"rank\").Over().PartitionBy(\"user_id\", \"kind\").OrderBy(rel.SortDesc(\"score\"), rel.SortAsc(\"id\")).As(\"rank\")",
+			expr:   rel.Fn("rank").Over().PartitionBy("user_id", "kind").OrderBy(rel.SortDesc("score"), rel.SortAsc("id")).As("rank"),
+		},
+		{
+			result: "rel.Fn(\"sum\", rel.Field(\"amount\")).Over().As(\"total\")",
+			expr:   rel.Fn("sum", rel.Field("amount")).Over().As("total"),
+		},
+		{
+			result: "rel.Case(rel.When(where.Gte(\"age\", 18), rel.Value(\"adult\")), rel.When(where.Gte(\"age\", 13), rel.Value(\"teen\"))).Else(rel.Value(\"child\")).As(\"group\")",
+			expr:   rel.Case(rel.When(where.Gte("age", 18), rel.Value("adult")), rel.When(where.Gte("age", 13), rel.Value("teen"))).Else(rel.Value("child")).As("group"),
+		},
+		{
+			result: "rel.Rank([]string{\"title\", \"body\"}, \"go orm\").As(\"score\")",
+			expr:   rel.Rank([]string{"title", "body"}, "go orm").As("score"),
+		},
+		{
+			result: "rel.Rank([]string{}, \"go\").As(\"score\")",
+			expr:   rel.Rank(nil, "go").As("score"),
+		},
+		{
+			result: "rel.Fn(\"rank\").Over().OrderBy(rel.SortDesc(rel.Rank([]string{\"title\"}, \"go\"))).As(\"position\")",
+			expr:   rel.Fn("rank").Over().OrderBy(rel.SortRank([]string{"title"}, "go")).As("position"),
+		},
+		{
+			result: "rel.Case(rel.When(where.Nil(\"deleted_at\"), rel.Field(\"name\"))).As(\"name\")",
+			expr:   rel.Case(rel.When(where.Nil("deleted_at"), rel.Field("name"))).As("name"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.result, func(t *testing.T) {
+			assert.Equal(t, test.result, test.expr.String())
+		})
+	}
+}
+
+func TestQuery_SelectExpr(t *testing.T) {
+	var (
+		count = rel.Fn("count", rel.Field("*")).As("n")
+		query = rel.From("users").Select("name").Group("name").SelectExpr(count)
+	)
+
+	assert.Equal(t, rel.SelectQuery{
+		Fields:      []string{"name"},
+		Expressions: []rel.SelectExpression{count},
+	}, query.SelectQuery)
+	assert.Equal(t, "rel.From(\"users\").Select(\"name\").SelectExpr(rel.Fn(\"count\", rel.Field(\"*\")).As(\"n\")).Group(\"name\")", query.String())
+}
@@ -1,9 +1,11 @@
 package rel
 
 // SelectQuery defines select clause of the query.
+// When Fields is empty and Expressions is not, all fields are selected along with the expressions.
 type SelectQuery struct {
 	OnlyDistinct bool
 	Fields       []string
+	Expressions  []SelectExpression
 }
 
 // Distinct select query.
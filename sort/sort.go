@@ -11,4 +11,7 @@ var (
 
 	// Desc creates a query that sort the result descending by specified field.
 	Desc = rel.NewSortDesc
+
+	// Rank creates a query that sort the result by full text search relevance, most relevant first.
+	Rank = rel.SortRank
 )
@@ -1,8 +1,10 @@
 package rel
 
 // SortQuery defines sort information of query.
+// When Expr is set, result is sorted by the expression instead of Field.
 type SortQuery struct {
 	Field string
+	Expr  Expr
 	Sort  int
 }
 
@@ -37,6 +39,14 @@ func SortDesc(field string) SortQuery {
 	}
 }
 
+// SortRank sorts by full text search relevance of query against fields, most relevant first.
+func SortRank(fields []string, query string) SortQuery {
+	return SortQuery{
+		Expr: Rank(fields, query),
+		Sort: -1,
+	}
+}
+
 var (
 	// NewSortAsc sorts field with ascending sort.
 	//
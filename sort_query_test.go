@@ -14,3 +14,10 @@ func TestSortQuery_Asc(t *testing.T) {
 func TestSortQuery_Desc(t *testing.T) {
 	assert.True(t, rel.SortDesc("score").Desc())
 }
+
+func TestSortRank(t *testing.T) {
+	sq := rel.SortRank([]string{"title", "body"}, "go orm")
+	assert.True(t, sq.Desc())
+	assert.Equal(t, "", sq.Field)
+	assert.Equal(t, rel.MatchExpr{Fields: []string{"title", "body"}, Query: "go orm"}, sq.Expr)
+}
@@ -0,0 +1,89 @@
+package rel
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrStreamPreload returned by Stream when query preloads association,
+// since preload query can't be executed while the stream cursor is open on the same connection.
+var ErrStreamPreload = errors.New("rel: preload is not supported by stream, preload each entity after the stream is closed or use FindAll")
+
+// streamIterator scans entities one row at a time from a single database cursor.
+type streamIterator struct {
+	cw         contextWrapper
+	repository repository
+	query      Query
+	cursor     Cursor
+	fields     []string
+	closed     bool
+}
+
+func (si *streamIterator) Close() error {
+	if !si.closed && si.cursor != nil {
+		si.closed = true
+		return si.cursor.Close()
+	}
+
+	return nil
+}
+
+func (si *streamIterator) Next(entity any) error {
+	if si.closed {
+		return io.EOF
+	}
+
+	doc := NewDocument(entity)
+	if si.cursor == nil {
+		if err := si.init(doc); err != nil {
+			return err
+		}
+	}
+
+	if !si.cursor.Next() {
+		return io.EOF
+	}
+
+	if err := si.cursor.Scan(doc.Scanners(si.fields)...); err != nil {
+		return err
+	}
+
+	return afterFind(si.cw.ctx, doc)
+}
+
+func (si *streamIterator) init(doc *Document) error {
+	if si.query.Table == "" {
+		si.query.Table = doc.Table()
+	}
+
+	if len(si.query.PreloadQuery) > 0 {
+		si.closed = true
+		return ErrStreamPreload
+	}
+
+	si.query = si.repository.withDefaultScope(si.cw.ctx, doc.meta, si.query.Populate(doc.meta), false)
+
+	cursor, err := si.cw.adapter.Query(si.cw.ctx, si.query)
+	if err != nil {
+		return err
+	}
+
+	fields, err := cursor.Fields()
+	if err != nil {
+		cursor.Close()
+		return err
+	}
+
+	si.cursor = cursor
+	si.fields = fields
+
+	return nil
+}
+
+func newStreamIterator(cw contextWrapper, r repository, query Query) Iterator {
+	return &streamIterator{
+		cw:         cw,
+		repository: r,
+		query:      query,
+	}
+}
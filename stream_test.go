@@ -0,0 +1,138 @@
+package rel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_Stream(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		query   = From("users").SortDesc("id").Limit(5)
+		cur     = createCursor(3)
+		it      = repo.Stream(context.TODO(), query)
+		count   = 0
+	)
+
+	adapter.On("Query", query).Return(cur, nil).Once()
+
+	for {
+		if err := it.Next(&user); err == io.EOF {
+			break
+		} else {
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, 10, user.ID)
+		count++
+	}
+
+	assert.Nil(t, it.Close())
+	assert.Nil(t, it.Close())
+	assert.Equal(t, io.EOF, it.Next(&user))
+	assert.Equal(t, 3, count)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Stream_setTableName(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		cur     = createCursor(1)
+		it      = repo.Stream(context.TODO(), Query{})
+	)
+
+	adapter.On("Query", Query{Table: "users"}).Return(cur, nil).Once()
+
+	assert.Nil(t, it.Next(&user))
+	assert.Equal(t, io.EOF, it.Next(&user))
+	assert.Nil(t, it.Close())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Stream_closeEarly(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		query   = From("users")
+		cur     = createCursor(3)
+		it      = repo.Stream(context.TODO(), query)
+	)
+
+	adapter.On("Query", query).Return(cur, nil).Once()
+
+	assert.Nil(t, it.Next(&user))
+	assert.Nil(t, it.Close())
+	assert.Equal(t, io.EOF, it.Next(&user))
+
+	adapter.AssertExpectations(t)
+	cur.AssertNumberOfCalls(t, "Next", 1)
+	cur.AssertCalled(t, "Close")
+}
+
+func TestRepository_Stream_withPreload(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		it      = repo.Stream(context.TODO(), From("users").Preload("address"))
+	)
+
+	assert.Equal(t, ErrStreamPreload, it.Next(&user))
+	assert.Equal(t, io.EOF, it.Next(&user))
+	assert.Nil(t, it.Close())
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Stream_queryError(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		query   = From("users")
+		err     = errors.New("error")
+		it      = repo.Stream(context.TODO(), query)
+	)
+
+	adapter.On("Query", query).Return(&testCursor{}, err).Once()
+
+	assert.Equal(t, err, it.Next(&user))
+	assert.Nil(t, it.Close())
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Stream_fieldsError(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		query   = From("users")
+		cur     = &testCursor{}
+		err     = errors.New("error")
+		it      = repo.Stream(context.TODO(), query)
+	)
+
+	adapter.On("Query", query).Return(cur, nil).Once()
+	cur.On("Fields").Return([]string(nil), err).Once()
+	cur.On("Close").Return(nil).Once()
+
+	assert.Equal(t, err, it.Next(&user))
+	assert.Nil(t, it.Close())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
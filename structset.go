@@ -63,7 +63,7 @@ func (s Structset) set(doc *Document, mut *Mutation, field string, value any, fo
 		panic(fmt.Sprint("rel: cannot assign ", value, " as ", field, " into ", doc.Table()))
 	}
 
-	mut.Add(Set(field, value))
+	mut.Add(Set(field, wrapJSON(s.doc.meta, field, value)))
 }
 
 func (s Structset) applyValue(doc *Document, mut *Mutation, field string, skipZero bool) {
@@ -251,6 +251,25 @@ func TestStructset_uuid(t *testing.T) {
 	assert.Equal(t, mutation, Apply(doc, NewStructset(&entity, false)))
 }
 
+func TestStructset_json(t *testing.T) {
+	var (
+		entity = struct {
+			ID       int
+			Settings map[string]any `db:"settings,json"`
+		}{ID: 1, Settings: map[string]any{"theme": "dark"}}
+		doc      = NewDocument(&entity)
+		mutation = Mutation{
+			Cascade: true,
+			Mutates: map[string]Mutate{
+				"id":       Set("id", 1),
+				"settings": Set("settings", jsonValue{value: map[string]any{"theme": "dark"}}),
+			},
+		}
+	)
+
+	assert.Equal(t, mutation, Apply(doc, NewStructset(&entity, false)))
+}
+
 func TestStructset_hasManyNotLoaded(t *testing.T) {
 	var (
 		user = User{
@@ -0,0 +1,181 @@
+package rel
+
+import (
+	"context"
+	"strings"
+)
+
+// TableResolver returns table name used by adapter for the table declared by entity or query.
+type TableResolver func(table string) string
+
+type tableResolverContextKey struct{}
+
+// WithTableResolver returns context that resolves table name of every query executed using the context,
+// including join, sub query and association tables. Table that is already qualified with schema is left as is.
+func WithTableResolver(ctx context.Context, resolver TableResolver) context.Context {
+	return context.WithValue(ctx, tableResolverContextKey{}, resolver)
+}
+
+// WithSchema returns context that qualifies table name of every query with the schema, eg: tenant_42.users.
+func WithSchema(ctx context.Context, schema string) context.Context {
+	return WithTableResolver(ctx, func(table string) string {
+		return schema + "." + table
+	})
+}
+
+// tableResolverAdapter rewrites table name of queries before passing it to the actual adapter.
+type tableResolverAdapter struct {
+	Adapter
+	resolve TableResolver
+}
+
+// withTableResolver wraps adapter with table resolver stored in the context if exists.
+func withTableResolver(ctx context.Context, adapter Adapter) Adapter {
+	resolve, ok := ctx.Value(tableResolverContextKey{}).(TableResolver)
+	if !ok || resolve == nil {
+		return adapter
+	}
+
+	if ra, ok := adapter.(tableResolverAdapter); ok {
+		adapter = ra.Adapter
+	}
+
+	return tableResolverAdapter{Adapter: adapter, resolve: resolve}
+}
+
+func (ra tableResolverAdapter) Aggregate(ctx context.Context, query Query, mode string, field string) (int, error) {
+	return ra.Adapter.Aggregate(ctx, resolveQuery(query, ra.resolve, nil), mode, field)
+}
+
+func (ra tableResolverAdapter) Query(ctx context.Context, query Query) (Cursor, error) {
+	return ra.Adapter.Query(ctx, resolveQuery(query, ra.resolve, nil))
+}
+
+func (ra tableResolverAdapter) Insert(ctx context.Context, query Query, primaryField string, mutates map[string]Mutate, onConflict OnConflict) (any, error) {
+	return ra.Adapter.Insert(ctx, resolveQuery(query, ra.resolve, nil), primaryField, mutates, onConflict)
+}
+
+func (ra tableResolverAdapter) InsertAll(ctx context.Context, query Query, primaryField string, fields []string, bulkMutates []map[string]Mutate, onConflict OnConflict) ([]any, error) {
+	return ra.Adapter.InsertAll(ctx, resolveQuery(query, ra.resolve, nil), primaryField, fields, bulkMutates, onConflict)
+}
+
+func (ra tableResolverAdapter) Update(ctx context.Context, query Query, primaryField string, mutates map[string]Mutate) (int, error) {
+	return ra.Adapter.Update(ctx, resolveQuery(query, ra.resolve, nil), primaryField, mutates)
+}
+
+func (ra tableResolverAdapter) Delete(ctx context.Context, query Query) (int, error) {
+	return ra.Adapter.Delete(ctx, resolveQuery(query, ra.resolve, nil))
+}
+
+func (ra tableResolverAdapter) SupportPartitionLimit() bool {
+	return SupportPartitionLimit(ra.Adapter)
+}
+
+// Begin returns the actual transaction adapter, it's wrapped again when fetched from context.
+func (ra tableResolverAdapter) Begin(ctx context.Context, options TransactionOptions) (Adapter, error) {
+	return ra.Adapter.Begin(ctx, options)
+}
+
+// resolveQuery returns copy of the query with its tables resolved, names of common table expression are skipped.
+func resolveQuery(query Query, resolve TableResolver, ctes map[string]struct{}) Query {
+	if len(query.WithQuery) > 0 {
+		scoped := make(map[string]struct{}, len(ctes)+len(query.WithQuery))
+		for name := range ctes {
+			scoped[name] = struct{}{}
+		}
+
+		for _, wq := range query.WithQuery {
+			scoped[wq.Name] = struct{}{}
+		}
+
+		ctes = scoped
+
+		withQuery := make([]WithQuery, len(query.WithQuery))
+		for i, wq := range query.WithQuery {
+			wq.Query = resolveQuery(wq.Query, resolve, ctes)
+			if wq.Recursive {
+				wq.RecursiveQuery = resolveQuery(wq.RecursiveQuery, resolve, ctes)
+			}
+
+			withQuery[i] = wq
+		}
+
+		query.WithQuery = withQuery
+	}
+
+	query.Table = resolveTable(query.Table, resolve, ctes)
+
+	if len(query.JoinQuery) > 0 {
+		joinQuery := make([]JoinQuery, len(query.JoinQuery))
+		for i, jq := range query.JoinQuery {
+			jq.Table = resolveTable(jq.Table, resolve, ctes)
+			jq.Filter = resolveFilter(jq.Filter, resolve, ctes)
+			joinQuery[i] = jq
+		}
+
+		query.JoinQuery = joinQuery
+	}
+
+	query.WhereQuery = resolveFilter(query.WhereQuery, resolve, ctes)
+	query.GroupQuery.Filter = resolveFilter(query.GroupQuery.Filter, resolve, ctes)
+
+	if len(query.CombinationQuery) > 0 {
+		combinationQuery := make([]CombinationQuery, len(query.CombinationQuery))
+		for i, cq := range query.CombinationQuery {
+			cq.Query = resolveQuery(cq.Query, resolve, ctes)
+			combinationQuery[i] = cq
+		}
+
+		query.CombinationQuery = combinationQuery
+	}
+
+	return query
+}
+
+// resolveFilter resolves tables of sub queries inside the filter.
+func resolveFilter(filter FilterQuery, resolve TableResolver, ctes map[string]struct{}) FilterQuery {
+	switch v := filter.Value.(type) {
+	case Query:
+		filter.Value = resolveQuery(v, resolve, ctes)
+	case SubQuery:
+		v.Query = resolveQuery(v.Query, resolve, ctes)
+		filter.Value = v
+	case []any:
+		values := make([]any, len(v))
+		for i := range v {
+			values[i] = resolveFilter(FilterQuery{Value: v[i]}, resolve, ctes).Value
+		}
+
+		filter.Value = values
+	}
+
+	if len(filter.Inner) > 0 {
+		inner := make([]FilterQuery, len(filter.Inner))
+		for i := range filter.Inner {
+			inner[i] = resolveFilter(filter.Inner[i], resolve, ctes)
+		}
+
+		filter.Inner = inner
+	}
+
+	return filter
+}
+
+// resolveTable resolves table name while keeping its alias, eg: "users as u".
+func resolveTable(table string, resolve TableResolver, ctes map[string]struct{}) string {
+	if table == "" {
+		return table
+	}
+
+	name, alias, hasAlias := strings.Cut(table, " ")
+	if _, ok := ctes[name]; ok || strings.Contains(name, ".") {
+		return table
+	}
+
+	name = resolve(name)
+	if hasAlias {
+		name += " " + alias
+	}
+
+	return name
+}
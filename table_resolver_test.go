@@ -0,0 +1,171 @@
+package rel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTableResolver(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		ctx     = WithSchema(context.TODO(), "tenant_42")
+		cw      = fetchContext(ctx, adapter)
+	)
+
+	assert.IsType(t, tableResolverAdapter{}, cw.adapter)
+	assert.Equal(t, adapter, cw.adapter.(tableResolverAdapter).Adapter)
+	assert.Equal(t, "tenant_42.users", cw.adapter.(tableResolverAdapter).resolve("users"))
+
+	cw = fetchContext(wrapContext(ctx, contextData{adapter: cw.adapter}).ctx, adapter)
+	assert.Equal(t, adapter, cw.adapter.(tableResolverAdapter).Adapter)
+
+	assert.Equal(t, adapter, fetchContext(context.TODO(), adapter).adapter)
+	assert.False(t, SupportPartitionLimit(cw.adapter))
+	assert.True(t, SupportPartitionLimit(fetchContext(ctx, testPartitionLimitAdapter{adapter}).adapter))
+}
+
+func TestResolveQuery(t *testing.T) {
+	resolve := func(table string) string {
+		return "tenant_42." + table
+	}
+
+	tests := []struct {
+		name   string
+		query  Query
+		result Query
+	}{
+		{
+			name:   "table",
+			query:  From("users"),
+			result: From("tenant_42.users"),
+		},
+		{
+			name:   "table with alias",
+			query:  From("users as u"),
+			result: From("tenant_42.users as u"),
+		},
+		{
+			name:   "qualified table",
+			query:  From("public.users"),
+			result: From("public.users"),
+		},
+		{
+			name:   "join",
+			query:  From("users").JoinOn("transactions", "users.id", "transactions.user_id"),
+			result: From("tenant_42.users").JoinOn("tenant_42.transactions", "users.id", "transactions.user_id"),
+		},
+		{
+			name:   "sub query",
+			query:  From("users").Where(In("id", From("transactions").Select("user_id")), Exists(From("addresses"))),
+			result: From("tenant_42.users").Where(In("id", From("tenant_42.transactions").Select("user_id")), Exists(From("tenant_42.addresses"))),
+		},
+		{
+			name:   "having sub query",
+			query:  From("users").Group("age").Having(Gt("age", Any(From("transactions").Select("user_id")))),
+			result: From("tenant_42.users").Group("age").Having(Gt("age", Any(From("tenant_42.transactions").Select("user_id")))),
+		},
+		{
+			name:   "combination",
+			query:  From("users").Select("id").UnionAll(From("admins").Select("id")),
+			result: From("tenant_42.users").Select("id").UnionAll(From("tenant_42.admins").Select("id")),
+		},
+		{
+			name:   "common table expression",
+			query:  From("active_users").With("active_users", From("users").Where(Eq("active", true))),
+			result: From("active_users").With("active_users", From("tenant_42.users").Where(Eq("active", true))),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.result, resolveQuery(test.query, resolve, nil))
+		})
+	}
+}
+
+func TestResolveQuery_immutable(t *testing.T) {
+	var (
+		query = From("users").JoinOn("transactions", "users.id", "transactions.user_id").Where(Exists(From("addresses")))
+		clone = From("users").JoinOn("transactions", "users.id", "transactions.user_id").Where(Exists(From("addresses")))
+	)
+
+	resolveQuery(query, func(table string) string { return "tenant_42." + table }, nil)
+	assert.Equal(t, clone, query)
+}
+
+func TestRepository_Find_schema(t *testing.T) {
+	var (
+		user    User
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithSchema(context.TODO(), "tenant_42")
+		cur     = createCursor(1)
+	)
+
+	adapter.On("Query", From("tenant_42.users").Where(Eq("id", 10)).Limit(1)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.Find(ctx, &user, Eq("id", 10)))
+	assert.Equal(t, 10, user.ID)
+	assert.Equal(t, "users", NewDocument(&user).Table())
+	assert.False(t, cur.Next())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Preload_schema(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTableResolver(context.TODO(), func(table string) string { return "acme_" + table })
+		user    = User{ID: 10}
+		cur     = &testCursor{}
+	)
+
+	adapter.On("Query", From("acme_transactions").Where(In("user_id", 10))).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "user_id"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(5, 10).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(ctx, &user, "transactions"))
+	assert.Len(t, user.Transactions, 1)
+	assert.Equal(t, 5, user.Transactions[0].ID)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Transaction_schema(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithSchema(context.TODO(), "tenant_42")
+		user    = User{Name: "rel"}
+		mutates = map[string]Mutate{
+			"name":       Set("name", "rel"),
+			"age":        Set("age", 0),
+			"created_at": Set("created_at", Now()),
+			"updated_at": Set("updated_at", Now()),
+		}
+	)
+
+	adapter.On("Begin", TransactionOptions{}).Return(nil).Once()
+	adapter.On("Insert", From("tenant_42.users"), mutates, OnConflict{}).Return(1, nil).Once()
+	adapter.On("Delete", From("tenant_42.users").Where(Eq("id", 1))).Return(1, nil).Once()
+	adapter.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, repo.Transaction(ctx, func(ctx context.Context) error {
+		if err := repo.Insert(ctx, &user); err != nil {
+			return err
+		}
+
+		return repo.Delete(ctx, &user)
+	}))
+
+	adapter.AssertExpectations(t)
+}
@@ -0,0 +1,222 @@
+package rel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrTenantRequired is returned when UpdateAny or DeleteAny is executed on tenant scoped table without tenant in context.
+var ErrTenantRequired = errors.New("rel: tenant is required")
+
+var tenantFields sync.Map
+
+type tenantContextKey struct{}
+
+// RegisterTenant registers tables of the entities as tenant scoped, entity declares its tenant field using `db:"tenant_id,tenant"` tag.
+// Table is registered as well once the entity is used by rel, but table only operations such as Count, Aggregate, UpdateAny
+// and DeleteAny only know the table name, register the entities before executing any query, eg: in init function,
+// so they are filtered by tenant and UpdateAny and DeleteAny are refused without tenant in context regardless of the order of queries.
+func RegisterTenant(entities ...any) {
+	for _, entity := range entities {
+		rt := reflect.TypeOf(entity)
+		for rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+
+		if rt.Kind() != reflect.Struct {
+			panic("rel: tenant entity must be a struct or pointer to a struct")
+		}
+
+		meta := getDocumentMeta(rt, false)
+		if meta.tenantField == "" {
+			panic("rel: tenant entity " + rt.Name() + " doesn't declare tenant field")
+		}
+
+		tenantFields.Store(meta.Table(), meta.tenantField)
+	}
+}
+
+// WithTenant returns context that scopes repository operations to the tenant.
+// Entity declares its tenant field using `db:"tenant_id,tenant"` tag, the field is filtered on every query
+// and set on every insert of the entity, including preload and cascaded operations.
+// Table only operations are only scoped for entities that are registered using RegisterTenant or already used by rel.
+func WithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns tenant stored in the context by WithTenant.
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(tenantContextKey{})
+	return tenant, tenant != nil
+}
+
+// tenantField returns tenant field registered for the table, empty if table is not tenant scoped.
+func tenantField(table string) string {
+	if field, ok := tenantFields.Load(table); ok {
+		return field.(string)
+	}
+
+	return ""
+}
+
+// withTenantScope filters query by tenant in the context, query is returned as is when there's no tenant.
+// Combined queries, common table expressions and sub-queries of tenant scoped table are filtered as well, regardless of unscoped query.
+func withTenantScope(ctx context.Context, field string, query Query) Query {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return query
+	}
+
+	return scopeTenant(tenant, field, query)
+}
+
+// scopeTenant filters query and its nested queries by the tenant, nested slices are copied to avoid modifying caller's query.
+func scopeTenant(tenant any, field string, query Query) Query {
+	if len(query.WithQuery) > 0 {
+		withs := make([]WithQuery, len(query.WithQuery))
+		for i, wq := range query.WithQuery {
+			wq.Query = scopeNestedTenant(tenant, query.Table, field, wq.Query)
+			if wq.Recursive {
+				wq.RecursiveQuery = scopeNestedTenant(tenant, query.Table, field, wq.RecursiveQuery)
+			}
+
+			withs[i] = wq
+		}
+
+		query.WithQuery = withs
+	}
+
+	if len(query.CombinationQuery) > 0 {
+		combinations := make([]CombinationQuery, len(query.CombinationQuery))
+		for i, cq := range query.CombinationQuery {
+			if cq.Query.Table == "" {
+				cq.Query.Table = query.Table
+			}
+
+			cq.Query = scopeNestedTenant(tenant, query.Table, field, cq.Query)
+			combinations[i] = cq
+		}
+
+		query.CombinationQuery = combinations
+	}
+
+	if filter, ok := scopeFilterTenant(tenant, query.Table, field, query.WhereQuery); ok {
+		query.WhereQuery = filter
+	}
+
+	if field != "" {
+		query = query.Where(Eq(field, tenant))
+	}
+
+	return query
+}
+
+// scopeNestedTenant filters nested query by the tenant, tenant field of the parent is used when both are of the same table.
+// Alias of the nested table is stripped before looking up its tenant field, and used to qualify the field.
+func scopeNestedTenant(tenant any, table string, field string, query Query) Query {
+	var (
+		name, alias, hasAlias = strings.Cut(query.Table, " ")
+		parent, _, _          = strings.Cut(table, " ")
+	)
+
+	if name != parent {
+		field = tenantField(name)
+	} else if i := strings.LastIndexByte(field, '.'); i >= 0 {
+		field = field[i+1:]
+	}
+
+	if aliases := strings.Fields(alias); field != "" && hasAlias && len(aliases) > 0 {
+		field = aliases[len(aliases)-1] + "." + field
+	}
+
+	return scopeTenant(tenant, field, query)
+}
+
+// scopeFilterTenant filters sub-queries of the filter by the tenant, the filter is copied when it's changed.
+func scopeFilterTenant(tenant any, table string, field string, fq FilterQuery) (FilterQuery, bool) {
+	if value, ok := scopeValueTenant(tenant, table, field, fq.Value); ok {
+		fq.Value = value
+		return fq, true
+	}
+
+	var inner []FilterQuery
+	for i := range fq.Inner {
+		if filter, ok := scopeFilterTenant(tenant, table, field, fq.Inner[i]); ok {
+			if inner == nil {
+				inner = append([]FilterQuery(nil), fq.Inner...)
+			}
+
+			inner[i] = filter
+		}
+	}
+
+	if inner == nil {
+		return fq, false
+	}
+
+	fq.Inner = inner
+	return fq, true
+}
+
+// requireTenantScope filters query by tenant in the context, and returns ErrTenantRequired if table is tenant scoped but there's no tenant.
+func requireTenantScope(ctx context.Context, query Query) (Query, error) {
+	field := tenantField(query.Table)
+	if field == "" {
+		return query, nil
+	}
+
+	if _, ok := TenantFromContext(ctx); !ok {
+		return query, ErrTenantRequired
+	}
+
+	return withTenantScope(ctx, field, query), nil
+}
+
+// applyTenant sets tenant field of the document and the mutation to tenant in the context.
+func applyTenant(ctx context.Context, doc *Document, mutation *Mutation) {
+	field := doc.meta.tenantField
+	if field == "" {
+		return
+	}
+
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if !doc.SetValue(field, tenant) {
+		panic(fmt.Sprint("rel: cannot assign ", tenant, " as ", field, " into ", doc.Table()))
+	}
+
+	mutation.Add(Set(field, tenant))
+}
+
+// scopeValueTenant filters sub-query used as filter value by the tenant, including sub-query inside In filter values.
+func scopeValueTenant(tenant any, table string, field string, value any) (any, bool) {
+	switch v := value.(type) {
+	case Query:
+		return scopeNestedTenant(tenant, table, field, v), true
+	case SubQuery:
+		v.Query = scopeNestedTenant(tenant, table, field, v.Query)
+		return v, true
+	case []any:
+		var values []any
+		for i := range v {
+			if sub, ok := scopeValueTenant(tenant, table, field, v[i]); ok {
+				if values == nil {
+					values = append([]any(nil), v...)
+				}
+
+				values[i] = sub
+			}
+		}
+
+		return values, values != nil
+	}
+
+	return value, false
+}
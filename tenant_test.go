@@ -0,0 +1,324 @@
+package rel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type TenantProject struct {
+	ID        int
+	AccountID int `db:"account_id,tenant"`
+	Name      string
+	Tasks     []TenantTask `ref:"id" fk:"project_id"`
+}
+
+type TenantTask struct {
+	ID        int
+	ProjectID int
+	AccountID int64 `db:"account_id,tenant"`
+}
+
+func TestWithTenant(t *testing.T) {
+	tenant, ok := TenantFromContext(context.TODO())
+	assert.False(t, ok)
+	assert.Nil(t, tenant)
+
+	tenant, ok = TenantFromContext(WithTenant(context.TODO(), 1))
+	assert.True(t, ok)
+	assert.Equal(t, 1, tenant)
+}
+
+func TestDocumentMeta_tenantField(t *testing.T) {
+	doc := NewDocument(&TenantProject{})
+	assert.Equal(t, "account_id", doc.meta.tenantField)
+	assert.Equal(t, []string{"id", "account_id", "name"}, doc.Fields())
+}
+
+func TestRegisterTenant(t *testing.T) {
+	type UsedTenant struct {
+		ID       int
+		TenantID int `db:"tenant_id,tenant"`
+	}
+
+	NewDocument(&UsedTenant{})
+	assert.Equal(t, "tenant_id", tenantField("used_tenants"))
+
+	RegisterTenant(TenantProject{})
+	assert.Equal(t, "account_id", tenantField("tenant_projects"))
+	assert.Equal(t, "", tenantField("users"))
+
+	assert.PanicsWithValue(t, "rel: tenant entity User doesn't declare tenant field", func() {
+		RegisterTenant(&User{})
+	})
+	assert.PanicsWithValue(t, "rel: tenant entity must be a struct or pointer to a struct", func() {
+		RegisterTenant(1)
+	})
+}
+
+func TestRepository_Find_tenant(t *testing.T) {
+	var (
+		project TenantProject
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTenant(context.TODO(), 1)
+		cur     = createCursor(1)
+	)
+
+	adapter.On("Query", From("tenant_projects").Where(Eq("id", 10), Eq("account_id", 1)).Unscoped().Limit(1)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.Find(ctx, &project, Eq("id", 10), Unscoped(true)))
+	assert.Equal(t, 10, project.ID)
+	assert.False(t, cur.Next())
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_FindAll_withoutTenant(t *testing.T) {
+	var (
+		projects []TenantProject
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		cur      = createCursor(1)
+	)
+
+	adapter.On("Query", From("tenant_projects")).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.FindAll(context.TODO(), &projects))
+	assert.Len(t, projects, 1)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_Count_tenant(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTenant(context.TODO(), 1)
+	)
+
+	RegisterTenant(&TenantProject{})
+	adapter.On("Aggregate", From("tenant_projects").Where(Eq("account_id", 1)), "count", "*").Return(1, nil).Once()
+
+	count, err := repo.Count(ctx, "tenant_projects")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Insert_tenant(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTenant(context.TODO(), 1)
+		project = TenantProject{Name: "rel", AccountID: 2}
+		mutates = map[string]Mutate{
+			"name":       Set("name", "rel"),
+			"account_id": Set("account_id", 1),
+		}
+	)
+
+	adapter.On("Insert", From("tenant_projects"), mutates, OnConflict{}).Return(1, nil).Once()
+
+	assert.Nil(t, repo.Insert(ctx, &project))
+	assert.Equal(t, TenantProject{ID: 1, Name: "rel", AccountID: 1}, project)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_InsertAll_tenant(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTenant(context.TODO(), 1)
+		tasks   = []TenantTask{{ProjectID: 1}, {ProjectID: 2}}
+		mutates = []map[string]Mutate{
+			{"project_id": Set("project_id", 1), "account_id": Set("account_id", 1)},
+			{"project_id": Set("project_id", 2), "account_id": Set("account_id", 1)},
+		}
+	)
+
+	adapter.On("InsertAll", From("tenant_tasks"), mock.Anything, mutates, OnConflict{}).Return([]any{1, 2}, nil).Once()
+
+	assert.Nil(t, repo.InsertAll(ctx, &tasks))
+	assert.Equal(t, []TenantTask{{ID: 1, ProjectID: 1, AccountID: 1}, {ID: 2, ProjectID: 2, AccountID: 1}}, tasks)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Insert_tenantInvalidType(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTenant(context.TODO(), "acme")
+	)
+
+	assert.Panics(t, func() {
+		_ = repo.Insert(ctx, &TenantProject{Name: "rel"})
+	})
+}
+
+func TestRepository_UpdateAny_tenant(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		query   = From("tenant_projects").Where(Eq("name", "rel"))
+		mutates = map[string]Mutate{
+			"name": Set("name", "go-rel"),
+		}
+	)
+
+	RegisterTenant(&TenantProject{})
+
+	_, err := repo.UpdateAny(context.TODO(), query, Set("name", "go-rel"))
+	assert.Equal(t, ErrTenantRequired, err)
+
+	adapter.On("Update", query.Where(Eq("account_id", 1)), "", mutates).Return(1, nil).Once()
+
+	updatedCount, err := repo.UpdateAny(WithTenant(context.TODO(), 1), query, Set("name", "go-rel"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, updatedCount)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_DeleteAny_tenant(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		query   = From("tenant_projects").Where(Eq("name", "rel"))
+	)
+
+	RegisterTenant(&TenantProject{})
+
+	_, err := repo.DeleteAny(context.TODO(), query)
+	assert.Equal(t, ErrTenantRequired, err)
+
+	adapter.On("Delete", query.Where(Eq("account_id", 1))).Return(1, nil).Once()
+
+	deletedCount, err := repo.DeleteAny(WithTenant(context.TODO(), 1), query)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, deletedCount)
+
+	adapter.AssertExpectations(t)
+}
+
+func TestRepository_Preload_tenant(t *testing.T) {
+	var (
+		adapter = &testAdapter{}
+		repo    = New(adapter)
+		ctx     = WithTenant(context.TODO(), 1)
+		project = TenantProject{ID: 10, AccountID: 1}
+		cur     = &testCursor{}
+	)
+
+	adapter.On("Query", From("tenant_tasks").Where(In("project_id", 10), Eq("account_id", 1))).Return(cur, nil).Once()
+
+	cur.On("Close").Return(nil).Once()
+	cur.On("Fields").Return([]string{"id", "project_id", "account_id"}, nil).Once()
+	cur.On("Next").Return(true).Once()
+	cur.MockScan(5, 10, 1).Once()
+	cur.On("Next").Return(false).Once()
+
+	assert.Nil(t, repo.Preload(ctx, &project, "tasks"))
+	assert.Equal(t, []TenantTask{{ID: 5, ProjectID: 10, AccountID: 1}}, project.Tasks)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_FindAll_tenantCombination(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		want  Query
+	}{
+		{
+			name:  "unscoped query",
+			query: From("tenant_projects").UnionAll(From("tenant_projects")).Unscoped(),
+			want:  From("tenant_projects").UnionAll(From("tenant_projects").Where(Eq("account_id", 1))).Unscoped().Where(Eq("account_id", 1)),
+		},
+		{
+			name:  "unscoped combination",
+			query: From("tenant_projects").UnionAll(From("tenant_projects").Unscoped()),
+			want:  From("tenant_projects").UnionAll(From("tenant_projects").Unscoped().Where(Eq("account_id", 1))).Where(Eq("account_id", 1)),
+		},
+		{
+			name:  "other table",
+			query: From("tenant_projects").Union(From("tenant_tasks").Select("project_id")).Unscoped(),
+			want:  From("tenant_projects").Union(From("tenant_tasks").Select("project_id").Where(Eq("account_id", 1))).Unscoped().Where(Eq("account_id", 1)),
+		},
+	}
+
+	RegisterTenant(TenantTask{})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				projects []TenantProject
+				adapter  = &testAdapter{}
+				repo     = New(adapter)
+				cur      = createCursor(0)
+			)
+
+			adapter.On("Query", test.want).Return(cur, nil).Once()
+
+			assert.Nil(t, repo.FindAll(WithTenant(context.TODO(), 1), &projects, test.query))
+
+			adapter.AssertExpectations(t)
+			cur.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRepository_FindAll_tenantNested(t *testing.T) {
+	var (
+		projects []TenantProject
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		cur      = createCursor(0)
+		tasks    = From("tenant_tasks").Select("project_id")
+		query    = From("tenant_projects").
+				With("pending", tasks.Where(Eq("done", false))).
+				Where(Or(In("id", tasks), Eq("id", Any(tasks)))).
+				Unscoped()
+	)
+
+	RegisterTenant(TenantTask{})
+	adapter.On("Query", From("tenant_projects").
+		With("pending", tasks.Where(Eq("done", false), Eq("account_id", 1))).
+		Where(Or(In("id", tasks.Where(Eq("account_id", 1))), Eq("id", Any(tasks.Where(Eq("account_id", 1)))))).
+		Unscoped().
+		Where(Eq("account_id", 1))).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.FindAll(WithTenant(context.TODO(), 1), &projects, query))
+	assert.Equal(t, From("tenant_tasks").Select("project_id"), tasks)
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
+
+func TestRepository_FindAll_tenantHasAssoc(t *testing.T) {
+	var (
+		projects []TenantProject
+		adapter  = &testAdapter{}
+		repo     = New(adapter)
+		cur      = createCursor(0)
+	)
+
+	RegisterTenant(TenantTask{})
+	adapter.On("Query", From("tenant_projects").Where(
+		NotExists(From("tenant_tasks as tasks").Where(Eq("tasks.project_id", Field("tenant_projects.id")), Eq("tasks.account_id", 1))),
+		Eq("account_id", 1),
+	)).Return(cur, nil).Once()
+
+	assert.Nil(t, repo.FindAll(WithTenant(context.TODO(), 1), &projects, NotHasAssoc("tasks")))
+
+	adapter.AssertExpectations(t)
+	cur.AssertExpectations(t)
+}
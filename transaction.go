@@ -0,0 +1,245 @@
+package rel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrIncompatibleTransaction returned when nested transaction requests options that can't be satisfied by outer transaction.
+var ErrIncompatibleTransaction = errors.New("rel: nested transaction options are incompatible with outer transaction")
+
+// IsolationLevel defines transaction isolation level.
+// Isolation levels are ordered from the weakest to the strongest.
+type IsolationLevel int8
+
+const (
+	// DefaultIsolation uses database default isolation level.
+	DefaultIsolation IsolationLevel = iota
+	// ReadUncommitted isolation level.
+	ReadUncommitted
+	// ReadCommitted isolation level.
+	ReadCommitted
+	// RepeatableRead isolation level.
+	RepeatableRead
+	// Serializable isolation level.
+	Serializable
+)
+
+// String representation of the isolation level.
+func (il IsolationLevel) String() string {
+	switch il {
+	case ReadUncommitted:
+		return "READ UNCOMMITTED"
+	case ReadCommitted:
+		return "READ COMMITTED"
+	case RepeatableRead:
+		return "REPEATABLE READ"
+	case Serializable:
+		return "SERIALIZABLE"
+	default:
+		return ""
+	}
+}
+
+func (il IsolationLevel) applyTransaction(config *transactionConfig) {
+	config.options.Isolation = il
+}
+
+// TransactionOptions defines options used by adapter when beginning a transaction.
+type TransactionOptions struct {
+	Isolation  IsolationLevel
+	ReadOnly   bool
+	Deferrable bool
+}
+
+// compatible returns true if nested transaction with given options can run inside transaction with this options.
+func (to TransactionOptions) compatible(nested TransactionOptions) bool {
+	return nested.Isolation <= to.Isolation &&
+		(!nested.ReadOnly || to.ReadOnly) &&
+		(!nested.Deferrable || to.Deferrable)
+}
+
+// transactionConfig holds options passed to adapter and options that are handled by repository.
+type transactionConfig struct {
+	options TransactionOptions
+	retry   RetryPolicy
+}
+
+// TransactionOption interface.
+// Available options are: Isolation, ReadOnly, Deferrable, Retry.
+type TransactionOption interface {
+	applyTransaction(config *transactionConfig)
+}
+
+func applyTransactionOptions(options []TransactionOption) transactionConfig {
+	var (
+		config transactionConfig
+	)
+
+	for i := range options {
+		options[i].applyTransaction(&config)
+	}
+
+	return config
+}
+
+// Isolation sets isolation level of the transaction.
+func Isolation(level IsolationLevel) TransactionOption {
+	return level
+}
+
+type readOnly bool
+
+func (ro readOnly) applyTransaction(config *transactionConfig) {
+	config.options.ReadOnly = bool(ro)
+}
+
+// ReadOnly sets transaction access mode to read only.
+func ReadOnly() TransactionOption {
+	return readOnly(true)
+}
+
+type deferrable bool
+
+func (d deferrable) applyTransaction(config *transactionConfig) {
+	config.options.Deferrable = bool(d)
+}
+
+// Deferrable sets transaction to be deferrable.
+// Only meaningful for serializable and read only transaction, and only supported by some databases.
+func Deferrable() TransactionOption {
+	return deferrable(true)
+}
+
+// RetryPolicy defines how transaction is retried when it failed with TransactionError,
+// such as serialization failure or deadlock.
+// Retry only happens at the outermost transaction, nested transaction will pass the error to the outer transaction.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times transaction is executed, including the first attempt.
+	MaxAttempts int
+	// Backoff returns duration to wait before given retry attempt (starts from 1).
+	Backoff func(attempt int) time.Duration
+}
+
+func (rp RetryPolicy) applyTransaction(config *transactionConfig) {
+	config.retry = rp
+}
+
+// retryable returns true if given error can be retried after given attempt.
+func (rp RetryPolicy) retryable(attempt int, err error) bool {
+	var (
+		te TransactionError
+	)
+
+	return attempt < rp.MaxAttempts && errors.As(err, &te)
+}
+
+// wait for backoff duration of given retry attempt, or until context is done.
+func (rp RetryPolicy) wait(ctx context.Context, attempt int) error {
+	if rp.Backoff == nil {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(rp.Backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// maxRetryBackoff is the maximum wait duration that Retry doubles backoff to.
+const maxRetryBackoff = time.Minute
+
+// Retry transaction up to maxAttempts times when it failed with TransactionError.
+// Wait duration before each retry starts with backoff and is doubled after each retry, up to one minute.
+func Retry(maxAttempts int, backoff time.Duration) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: maxAttempts,
+		Backoff: func(attempt int) time.Duration {
+			wait := backoff
+			for i := 1; i < attempt && wait < maxRetryBackoff; i++ {
+				wait <<= 1
+			}
+
+			return min(wait, max(backoff, maxRetryBackoff))
+		},
+	}
+}
+
+// transactionCallbacks stores callbacks registered during the root transaction.
+type transactionCallbacks struct {
+	lock          sync.Mutex
+	afterCommit   []func()
+	afterRollback []func()
+}
+
+// mark returns current number of registered callbacks, used to discard callbacks registered after savepoint.
+func (tc *transactionCallbacks) mark() (int, int) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	return len(tc.afterCommit), len(tc.afterRollback)
+}
+
+// rollbackTo discards callbacks registered after the mark, after rollback callbacks are only fired by the root transaction.
+func (tc *transactionCallbacks) rollbackTo(commitMark int, rollbackMark int) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+
+	tc.afterCommit = tc.afterCommit[:commitMark]
+	tc.afterRollback = tc.afterRollback[:rollbackMark]
+}
+
+func (tc *transactionCallbacks) fire(committed bool) {
+	tc.lock.Lock()
+	var (
+		fns = tc.afterRollback
+	)
+
+	if committed {
+		fns = tc.afterCommit
+	}
+
+	tc.afterCommit = nil
+	tc.afterRollback = nil
+	tc.lock.Unlock()
+
+	for i := range fns {
+		fns[i]()
+	}
+}
+
+// AfterCommit registers fn to be called after the outermost transaction is committed.
+// Callbacks are called once in the order they're registered.
+// If there's no active transaction in the context, fn is called immediately.
+func AfterCommit(ctx context.Context, fn func()) {
+	if data, ok := ctx.Value(ctxKey).(contextData); ok && data.callbacks != nil {
+		data.callbacks.lock.Lock()
+		data.callbacks.afterCommit = append(data.callbacks.afterCommit, fn)
+		data.callbacks.lock.Unlock()
+		return
+	}
+
+	fn()
+}
+
+// AfterRollback registers fn to be called after the outermost transaction is rolled back.
+// If fn is registered inside nested transaction that is rolled back, it's discarded along with the nested transaction.
+// Callbacks are called once in the order they're registered.
+// If there's no active transaction in the context, fn is called immediately.
+func AfterRollback(ctx context.Context, fn func()) {
+	if data, ok := ctx.Value(ctxKey).(contextData); ok && data.callbacks != nil {
+		data.callbacks.lock.Lock()
+		data.callbacks.afterRollback = append(data.callbacks.afterRollback, fn)
+		data.callbacks.lock.Unlock()
+		return
+	}
+
+	fn()
+}
@@ -0,0 +1,96 @@
+package rel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionOptions(t *testing.T) {
+	assert.Equal(t, transactionConfig{}, applyTransactionOptions(nil))
+	assert.Equal(t, TransactionOptions{
+		Isolation:  Serializable,
+		ReadOnly:   true,
+		Deferrable: true,
+	}, applyTransactionOptions([]TransactionOption{Isolation(Serializable), ReadOnly(), Deferrable()}).options)
+}
+
+func TestTransactionOptions_compatible(t *testing.T) {
+	tests := []struct {
+		name       string
+		outer      TransactionOptions
+		nested     TransactionOptions
+		compatible bool
+	}{
+		{
+			name:       "default",
+			compatible: true,
+		},
+		{
+			name:       "weaker isolation",
+			outer:      TransactionOptions{Isolation: Serializable},
+			nested:     TransactionOptions{Isolation: ReadCommitted},
+			compatible: true,
+		},
+		{
+			name:       "stronger isolation",
+			outer:      TransactionOptions{Isolation: ReadCommitted},
+			nested:     TransactionOptions{Isolation: Serializable},
+			compatible: false,
+		},
+		{
+			name:       "read write inside read only",
+			outer:      TransactionOptions{ReadOnly: true},
+			compatible: true,
+		},
+		{
+			name:       "read only inside read write",
+			nested:     TransactionOptions{ReadOnly: true},
+			compatible: false,
+		},
+		{
+			name:       "deferrable inside not deferrable",
+			nested:     TransactionOptions{Deferrable: true},
+			compatible: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.compatible, test.outer.compatible(test.nested))
+		})
+	}
+}
+
+func TestIsolationLevel_String(t *testing.T) {
+	assert.Equal(t, "", DefaultIsolation.String())
+	assert.Equal(t, "READ UNCOMMITTED", ReadUncommitted.String())
+	assert.Equal(t, "READ COMMITTED", ReadCommitted.String())
+	assert.Equal(t, "REPEATABLE READ", RepeatableRead.String())
+	assert.Equal(t, "SERIALIZABLE", Serializable.String())
+}
+
+func TestAfterCommit_withoutTransaction(t *testing.T) {
+	var (
+		calls []string
+	)
+
+	AfterCommit(context.TODO(), func() { calls = append(calls, "commit") })
+	AfterRollback(context.TODO(), func() { calls = append(calls, "rollback") })
+
+	assert.Equal(t, []string{"commit", "rollback"}, calls)
+}
+
+func TestRetry_backoff(t *testing.T) {
+	policy := Retry(100, time.Second)
+
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, 2*time.Second, policy.Backoff(2))
+	assert.Equal(t, 32*time.Second, policy.Backoff(6))
+	assert.Equal(t, time.Minute, policy.Backoff(7))
+	assert.Equal(t, time.Minute, policy.Backoff(100))
+	assert.Equal(t, time.Hour, Retry(100, time.Hour).Backoff(100))
+	assert.Equal(t, time.Duration(0), Retry(100, 0).Backoff(100))
+}
@@ -70,6 +70,60 @@ var (
 	// NotLike compares value of field to not match string pattern.
 	NotLike = rel.NotLike
 
+	// Between compares value of field to be within lower and upper value (inclusive).
+	Between = rel.Between
+
+	// NotBetween compares value of field to be outside of lower and upper value.
+	NotBetween = rel.NotBetween
+
+	// ILike compares value of field to match string pattern, ignoring case.
+	ILike = rel.ILike
+
+	// NotILike compares value of field to not match string pattern, ignoring case.
+	NotILike = rel.NotILike
+
+	// Regexp compares value of field to match regular expression pattern.
+	Regexp = rel.Regexp
+
+	// NotRegexp compares value of field to not match regular expression pattern.
+	NotRegexp = rel.NotRegexp
+
+	// Contains check whether array or json value of field contains all elements of the value.
+	Contains = rel.Contains
+
+	// ContainedBy check whether all elements of array or json value of field are contained by the value.
+	ContainedBy = rel.ContainedBy
+
+	// Overlaps check whether array value of field has any element in common with the value.
+	Overlaps = rel.Overlaps
+
+	// JSONEq compares value at the path inside json field to be equal with the value.
+	JSONEq = rel.JSONEq
+
+	// JSONNe compares value at the path inside json field to be not equal with the value.
+	JSONNe = rel.JSONNe
+
+	// JSONHasKey check whether the path exists inside json field.
+	JSONHasKey = rel.JSONHasKey
+
+	// JSONNotHasKey check whether the path doesn't exist inside json field.
+	JSONNotHasKey = rel.JSONNotHasKey
+
+	// Match filters record using full text search of query against fields.
+	Match = rel.Match
+
 	// Fragment add custom filter.
 	Fragment = rel.FilterFragment
+
+	// Exists check whether sub query returns any result.
+	Exists = rel.Exists
+
+	// NotExists check whether sub query returns no result.
+	NotExists = rel.NotExists
+
+	// HasAssoc check whether entity has any association that matches the filters.
+	HasAssoc = rel.HasAssoc
+
+	// NotHasAssoc check whether entity has no association that matches the filters.
+	NotHasAssoc = rel.NotHasAssoc
 )
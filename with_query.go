@@ -0,0 +1,63 @@
+package rel
+
+import (
+	"strings"
+)
+
+// WithQuery defines a common table expression (CTE) that can be referenced by its name as a table in the query.
+//
+// When Recursive is true, Query is used as the anchor member and RecursiveQuery is used as the recursive member,
+// both are combined using UNION ALL. RecursiveQuery can reference the common table expression by its name.
+type WithQuery struct {
+	Name           string
+	Query          Query
+	Recursive      bool
+	RecursiveQuery Query
+}
+
+// Build query.
+func (wq WithQuery) Build(query *Query) {
+	query.WithQuery = append(query.WithQuery, wq)
+}
+
+// String representation.
+func (wq WithQuery) String() string {
+	var builder strings.Builder
+
+	if wq.Recursive {
+		builder.WriteString("rel.WithRecursive(\"")
+	} else {
+		builder.WriteString("rel.With(\"")
+	}
+
+	builder.WriteString(wq.Name)
+	builder.WriteString("\", ")
+	builder.WriteString(wq.Query.String())
+
+	if wq.Recursive {
+		builder.WriteString(", ")
+		builder.WriteString(wq.RecursiveQuery.String())
+	}
+
+	builder.WriteByte(')')
+
+	return builder.String()
+}
+
+// NewWith defines a common table expression using given query.
+func NewWith(name string, query Query) WithQuery {
+	return WithQuery{
+		Name:  name,
+		Query: query,
+	}
+}
+
+// NewWithRecursive defines a recursive common table expression using given anchor and recursive query.
+func NewWithRecursive(name string, anchor Query, recursive Query) WithQuery {
+	return WithQuery{
+		Name:           name,
+		Query:          anchor,
+		Recursive:      true,
+		RecursiveQuery: recursive,
+	}
+}
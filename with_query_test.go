@@ -0,0 +1,27 @@
+package rel_test
+
+import (
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithQuery_String(t *testing.T) {
+	assert.Equal(t, "rel.With(\"adults\", rel.From(\"users\").Where(where.Gte(\"age\", 18)))", rel.NewWith("adults", rel.From("users").Where(where.Gte("age", 18))).String())
+	assert.Equal(t, "rel.WithRecursive(\"tree\", rel.From(\"categories\"), rel.From(\"categories\").JoinWith(\"JOIN\", \"tree\", \"tree.id\", \"categories.parent_id\"))",
+		rel.NewWithRecursive("tree", rel.From("categories"), rel.From("categories").JoinOn("tree", "tree.id", "categories.parent_id")).String())
+}
+
+func TestQuery_With(t *testing.T) {
+	var (
+		adults = rel.From("users").Where(where.Gte("age", 18))
+		query  = rel.With("adults", adults).With("seniors", rel.From("adults").Where(where.Gte("age", 60)))
+	)
+
+	assert.Equal(t, []rel.WithQuery{
+		rel.NewWith("adults", adults),
+		rel.NewWith("seniors", rel.From("adults").Where(where.Gte("age", 60))),
+	}, query.WithQuery)
+}